@@ -27,6 +27,8 @@ type AuthorizationSpec struct {
 	Organization string `json:"organization"`
 	// Description is a string which describes any useful details
 	// regarding the purpose or identity of the authorization token.
+	// It is suffixed with a marker identifying this resource, which allows
+	// authorizations to be recovered when their creation is not recorded.
 	Description string `json:"description"`
 	// Permissions is the set of permissions (policy) associated
 	// with the authorization token.
@@ -34,6 +36,18 @@ type AuthorizationSpec struct {
 
 	// Token is a target in which to store the resulting token string
 	Token Token `json:"token"`
+
+	// RotateAfter is the maximum age of a token (e.g. 720h), after which it is
+	// replaced by a newly created authorization. Tokens are not rotated by age when empty.
+	RotateAfter string `json:"rotateAfter,omitempty"`
+	// GracePeriod is the duration for which a rotated token remains valid after its
+	// replacement has been stored (e.g. 10m). It defaults to 1h.
+	GracePeriod string `json:"gracePeriod,omitempty"`
+
+	// DeletionPolicy determines whether the authorization is removed from
+	// each target Influx instance when this resource is deleted.
+	//+kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // Permission represents the ability to perform and action
@@ -49,42 +63,214 @@ type Permission struct {
 // target resource specifier.
 type Action string
 
+const (
+	ActionRead  Action = "read"
+	ActionWrite Action = "write"
+)
+
 // Resource represents a single or collection of resources of a single type.
 type Resource struct {
 	ResourceType ResourceType `json:"type"`
-	Name         string       `json:"name"`
+	// Name is the name of the resource of the corresponding kind (e.g. a Bucket)
+	// within the namespace of the authorization. When empty, the permission
+	// applies to every resource of the type within the organization.
+	Name string `json:"name,omitempty"`
 }
 
+//+kubebuilder:validation:Enum=annotations;authorizations;buckets;checks;dashboards;dbrp;documents;labels;notebooks;notificationEndpoints;notificationRules;orgs;remotes;replications;scrapers;secrets;sources;tasks;telegrafs;users;variables;views
+
 // ResourceType represents the type of a target resource.
 type ResourceType string
 
+const (
+	ResourceTypeAnnotations           ResourceType = "annotations"
+	ResourceTypeAuthorizations        ResourceType = "authorizations"
+	ResourceTypeBuckets               ResourceType = "buckets"
+	ResourceTypeChecks                ResourceType = "checks"
+	ResourceTypeDashboards            ResourceType = "dashboards"
+	ResourceTypeDBRP                  ResourceType = "dbrp"
+	ResourceTypeDocuments             ResourceType = "documents"
+	ResourceTypeLabels                ResourceType = "labels"
+	ResourceTypeNotebooks             ResourceType = "notebooks"
+	ResourceTypeNotificationEndpoints ResourceType = "notificationEndpoints"
+	ResourceTypeNotificationRules     ResourceType = "notificationRules"
+	ResourceTypeOrgs                  ResourceType = "orgs"
+	ResourceTypeRemotes               ResourceType = "remotes"
+	ResourceTypeReplications          ResourceType = "replications"
+	ResourceTypeScrapers              ResourceType = "scrapers"
+	ResourceTypeSecrets               ResourceType = "secrets"
+	ResourceTypeSources               ResourceType = "sources"
+	ResourceTypeTasks                 ResourceType = "tasks"
+	ResourceTypeTelegrafs             ResourceType = "telegrafs"
+	ResourceTypeUsers                 ResourceType = "users"
+	ResourceTypeVariables             ResourceType = "variables"
+	ResourceTypeViews                 ResourceType = "views"
+)
+
+// resourceTypes is every supported resource type.
+var resourceTypes = []ResourceType{
+	ResourceTypeAnnotations, ResourceTypeAuthorizations, ResourceTypeBuckets, ResourceTypeChecks,
+	ResourceTypeDashboards, ResourceTypeDBRP, ResourceTypeDocuments, ResourceTypeLabels,
+	ResourceTypeNotebooks, ResourceTypeNotificationEndpoints, ResourceTypeNotificationRules,
+	ResourceTypeOrgs, ResourceTypeRemotes, ResourceTypeReplications, ResourceTypeScrapers,
+	ResourceTypeSecrets, ResourceTypeSources, ResourceTypeTasks, ResourceTypeTelegrafs,
+	ResourceTypeUsers, ResourceTypeVariables, ResourceTypeViews,
+}
+
+// Referenceable reports whether a permission can name a single resource of the
+// type, which requires a paradox resource of the corresponding kind.
+func (t ResourceType) Referenceable() bool {
+	switch t {
+	case ResourceTypeOrgs, ResourceTypeBuckets, ResourceTypeTasks, ResourceTypeChecks,
+		ResourceTypeNotificationEndpoints, ResourceTypeNotificationRules,
+		ResourceTypeDashboards, ResourceTypeLabels, ResourceTypeUsers:
+		return true
+	default:
+		return false
+	}
+}
+
 // Token is a structure which identifies a destination for
 // the resulting secret token string generated when creating the
 // Authorization in a target instance.
+// Every target is kept in sync whenever the token is rotated.
 type Token struct {
 	SecretSpec *SecretSpec `json:"secretSpec,omitempty"`
+	// Secrets is a list of further Secrets in which to store the token.
+	Secrets []SecretSpec `json:"secrets,omitempty"`
+	// ConfigMaps is a list of ConfigMaps in which to publish non-sensitive
+	// connection details alongside the token, such as the instance address.
+	ConfigMaps []ConfigMapSpec `json:"configMaps,omitempty"`
 }
 
-// SecretSpec defines a specification for defining a Secret.
-type SecretSpec struct {
-	Namespace string `json:"namespace"`
+// SelectsNamespaces reports whether any target selects its namespaces by label.
+func (t Token) SelectsNamespaces() bool {
+	if t.SecretSpec != nil && t.SecretSpec.NamespaceSelector != nil {
+		return true
+	}
+
+	for _, spec := range t.Secrets {
+		if spec.NamespaceSelector != nil {
+			return true
+		}
+	}
+
+	for _, spec := range t.ConfigMaps {
+		if spec.NamespaceSelector != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TargetSpec defines the namespaces, name and contents of an object in which
+// details of a token are published.
+// The name, labels, annotations and data of the object are Go text templates
+// which are supplied with details of the target instance associated with the
+// token being stored:
+//
+//	.Instance.Namespace, .Instance.Name, .Instance.Address
+//	.Organization.Name, .Organization.ID
+//	.Bucket.Name, .Bucket.ID (the first bucket named by the permissions)
+//	.Buckets (each bucket named by the permissions)
+//	.Authorization.Namespace, .Authorization.Name, .Authorization.ID
+//	.Token (Secrets only)
+type TargetSpec struct {
+	// Namespace is the namespace in which the object is created.
+	Namespace string `json:"namespace,omitempty"`
+	// NamespaceSelector selects further namespaces, by label, in which the object is created.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 	// NameTemplate is a template which is supplied with details of the target
 	// instance associated with the token being stored.
+	// It cannot refer to the authorization ID or token, which change on rotation.
+	// Names must differ between the instances targeted by the organization, such as
+	// by referring to .Instance.Name, as targets are not shared between instances.
 	NameTemplate string `json:"nameTemplate"`
+	// Labels is a set of label templates which are added to the object.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations is a set of annotation templates which are added to the object.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Data is a set of templates for additional keys in the data field of the
+	// object, e.g. INFLUX_URL: "{{ .Instance.Address }}".
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// SecretSpec defines a specification for defining a Secret.
+type SecretSpec struct {
+	TargetSpec `json:",inline"`
 	// Key is the resulting key in the Secret data field under which the token
 	// will be stored.
 	Key string `json:"key"`
 }
 
+// ConfigMapSpec defines a specification for defining a ConfigMap.
+type ConfigMapSpec struct {
+	TargetSpec `json:",inline"`
+}
+
 // AuthorizationStatus defines the observed state of Authorization
 type AuthorizationStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the authorization.
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
 	Instances Instances `json:"instances"`
+	// Tokens records the state of the token issued in each target instance.
+	Tokens TokenInstances `json:"tokens,omitempty"`
+}
+
+// TokenInstances is a map of namespace to map of name to token instance.
+type TokenInstances map[string]map[string]TokenInstance
+
+// Get returns the token instance recorded for the instance identified by namespace and name.
+func (t TokenInstances) Get(namespace, name string) TokenInstance {
+	return t[namespace][name]
+}
+
+// Set records token as the token instance for the instance identified by namespace and name.
+func (t TokenInstances) Set(namespace, name string, token TokenInstance) {
+	namespaced, ok := t[namespace]
+	if !ok {
+		namespaced = map[string]TokenInstance{}
+		t[namespace] = namespaced
+	}
+
+	namespaced[name] = token
+}
+
+// TokenInstance is the state of the token issued for an authorization
+// within a single target instance.
+type TokenInstance struct {
+	// CreatedTime is the time at which the current token was created.
+	CreatedTime *metav1.Time `json:"createdTime,omitempty"`
+	// PermissionsHash identifies the permissions with which the current token was created.
+	PermissionsHash string `json:"permissionsHash,omitempty"`
+	// RotateRequest is the value of the rotate annotation when the current token was created.
+	RotateRequest string `json:"rotateRequest,omitempty"`
+	// Retired is the set of previous tokens which remain valid until their grace period ends.
+	Retired []RetiredToken `json:"retired,omitempty"`
+}
+
+// RetiredToken is a rotated token which is pending deletion.
+type RetiredToken struct {
+	// ID is the identifier of the rotated authorization.
+	ID InfluxID `json:"id"`
+	// DeleteAfter is the time after which the rotated authorization is deleted.
+	DeleteAfter metav1.Time `json:"deleteAfter"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
-// Authorization is the Schema for the authorizations API
+// Authorization is the Schema for the authorizations API.
+// The token of an authorization can be rotated on demand by setting the
+// paradox.macro.re/rotate annotation to a new value.
 type Authorization struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -0,0 +1,148 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var authorizationlog = logf.Log.WithName("authorization-resource")
+
+func (r *Authorization) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-paradox-macro-re-v1alpha1-authorization,mutating=false,failurePolicy=fail,sideEffects=None,groups=paradox.macro.re,resources=authorizations,verbs=create;update,versions=v1alpha1,name=vauthorization.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Authorization{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Authorization) ValidateCreate() error {
+	authorizationlog.Info("validate create", "name", r.Name)
+
+	return invalid("Authorization", r.Name, r.validate())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Authorization) ValidateUpdate(old runtime.Object) error {
+	authorizationlog.Info("validate update", "name", r.Name)
+
+	return invalid("Authorization", r.Name, validateUpdate(r, old, func(a *Authorization) interface{} { return a.Spec }))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Authorization) ValidateDelete() error {
+	return nil
+}
+
+func (r *Authorization) validate() (errs field.ErrorList) {
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateRequired(path.Child("organization"), r.Spec.Organization)...)
+	errs = append(errs, validateDuration(path.Child("rotateAfter"), r.Spec.RotateAfter)...)
+	errs = append(errs, validateDuration(path.Child("gracePeriod"), r.Spec.GracePeriod)...)
+
+	for i, permission := range r.Spec.Permissions {
+		errs = append(errs, validatePermission(path.Child("permissions").Index(i), permission)...)
+	}
+
+	tokenPath := path.Child("token")
+	if spec := r.Spec.Token.SecretSpec; spec != nil {
+		errs = append(errs, validateSecretSpec(tokenPath.Child("secretSpec"), *spec)...)
+	}
+
+	for i, spec := range r.Spec.Token.Secrets {
+		errs = append(errs, validateSecretSpec(tokenPath.Child("secrets").Index(i), spec)...)
+	}
+
+	for i, spec := range r.Spec.Token.ConfigMaps {
+		errs = append(errs, validateTargetSpec(tokenPath.Child("configMaps").Index(i), spec.TargetSpec)...)
+	}
+
+	return errs
+}
+
+// validatePermission checks that permission has a supported action and resource
+// type, and only names a resource when the type can be referenced.
+func validatePermission(path *field.Path, permission Permission) (errs field.ErrorList) {
+	switch permission.Action {
+	case ActionRead, ActionWrite:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("action"), permission.Action,
+			[]string{string(ActionRead), string(ActionWrite)}))
+	}
+
+	resourcePath := path.Child("resource")
+
+	supported := false
+	for _, resourceType := range resourceTypes {
+		supported = supported || resourceType == permission.Resource.ResourceType
+	}
+
+	if !supported {
+		values := make([]string, len(resourceTypes))
+		for i, resourceType := range resourceTypes {
+			values[i] = string(resourceType)
+		}
+
+		errs = append(errs, field.NotSupported(resourcePath.Child("type"), permission.Resource.ResourceType, values))
+	} else if permission.Resource.Name != "" && !permission.Resource.ResourceType.Referenceable() {
+		errs = append(errs, field.Forbidden(resourcePath.Child("name"),
+			"resources of type "+string(permission.Resource.ResourceType)+" cannot be referenced by name"))
+	}
+
+	return errs
+}
+
+func validateSecretSpec(path *field.Path, spec SecretSpec) (errs field.ErrorList) {
+	errs = append(errs, validateRequired(path.Child("key"), spec.Key)...)
+	errs = append(errs, validateTargetSpec(path, spec.TargetSpec)...)
+
+	return errs
+}
+
+// validateTargetSpec checks that every template of spec parses, and that its
+// namespace selector is valid.
+func validateTargetSpec(path *field.Path, spec TargetSpec) (errs field.ErrorList) {
+	errs = append(errs, validateLabelSelector(path.Child("namespaceSelector"), spec.NamespaceSelector)...)
+
+	errs = append(errs, validateRequired(path.Child("nameTemplate"), spec.NameTemplate)...)
+	if spec.NameTemplate != "" {
+		errs = append(errs, validateTemplate(path.Child("nameTemplate"), spec.NameTemplate)...)
+	}
+
+	for key, value := range spec.Labels {
+		errs = append(errs, validateTemplate(path.Child("labels").Key(key), value)...)
+	}
+
+	for key, value := range spec.Annotations {
+		errs = append(errs, validateTemplate(path.Child("annotations").Key(key), value)...)
+	}
+
+	for key, value := range spec.Data {
+		errs = append(errs, validateTemplate(path.Child("data").Key(key), value)...)
+	}
+
+	return errs
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAuthorizationValidate(t *testing.T) {
+	secret := func(nameTemplate, key string) SecretSpec {
+		return SecretSpec{TargetSpec: TargetSpec{Namespace: "default", NameTemplate: nameTemplate}, Key: key}
+	}
+
+	tests := []struct {
+		name string
+		spec AuthorizationSpec
+		want []string
+	}{
+		{
+			name: "valid",
+			spec: AuthorizationSpec{
+				Organization: "macro",
+				RotateAfter:  "720h",
+				Permissions: []Permission{
+					{Action: ActionRead, Resource: Resource{ResourceType: ResourceTypeBuckets, Name: "metrics"}},
+					{Action: ActionWrite, Resource: Resource{ResourceType: ResourceTypeDashboards, Name: "overview"}},
+				},
+				Token: Token{
+					SecretSpec: &SecretSpec{TargetSpec: TargetSpec{Namespace: "default", NameTemplate: "telegraf-{{ .Instance.Name }}"}, Key: "token"},
+					Secrets:    []SecretSpec{secret("{{ .Organization.Name }}-token", "INFLUX_TOKEN")},
+				},
+			},
+		},
+		{
+			name: "organization required",
+			want: []string{"FieldValueRequired spec.organization"},
+		},
+		{
+			name: "invalid durations",
+			spec: AuthorizationSpec{Organization: "macro", RotateAfter: "30d", GracePeriod: "-1h"},
+			want: []string{"FieldValueInvalid spec.rotateAfter", "FieldValueInvalid spec.gracePeriod"},
+		},
+		{
+			name: "invalid permissions",
+			spec: AuthorizationSpec{
+				Organization: "macro",
+				Permissions: []Permission{
+					{Action: "delete", Resource: Resource{ResourceType: ResourceTypeBuckets}},
+					{Action: ActionRead, Resource: Resource{ResourceType: "clusters"}},
+					{Action: ActionRead, Resource: Resource{ResourceType: ResourceTypeVariables, Name: "region"}},
+				},
+			},
+			want: []string{
+				"FieldValueNotSupported spec.permissions[0].action",
+				"FieldValueNotSupported spec.permissions[1].resource.type",
+				"FieldValueForbidden spec.permissions[2].resource.name",
+			},
+		},
+		{
+			name: "invalid token targets",
+			spec: AuthorizationSpec{
+				Organization: "macro",
+				Token: Token{
+					Secrets: []SecretSpec{secret("telegraf-{{ .Instance.Name", "")},
+					ConfigMaps: []ConfigMapSpec{{TargetSpec: TargetSpec{
+						Namespace: "default",
+						Data:      map[string]string{"url": "{{ .Instance.Address"},
+					}}},
+				},
+			},
+			want: []string{
+				"FieldValueRequired spec.token.secrets[0].key",
+				"FieldValueInvalid spec.token.secrets[0].nameTemplate",
+				"FieldValueRequired spec.token.configMaps[0].nameTemplate",
+				"FieldValueInvalid spec.token.configMaps[0].data[url]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorization := &Authorization{Spec: tt.spec}
+
+			got := errorFields(authorization.validate())
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
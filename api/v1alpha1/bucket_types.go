@@ -29,24 +29,98 @@ type BucketSpec struct {
 	Organization string `json:"organization"`
 	// Description is a string which describes any useful details
 	// regarding the purpose or identity of the bucket.
-	Description     string     `json:"description,omitempty"`
-	SchemaType      SchemaType `json:"schema_type,omitempty"`
-	RetentionPolicy string     `json:"retention_policy,omitempty"`
+	Description string `json:"description,omitempty"`
+	// SchemaType determines whether the bucket accepts writes of any measurement (implicit),
+	// or only those declared by its measurement schemas (explicit). It cannot be changed
+	// once the bucket has been created.
+	//+kubebuilder:default=implicit
+	SchemaType SchemaType `json:"schema_type,omitempty"`
+	// RetentionPolicy is the duration for which data is retained (e.g. 720h).
+	// Data is retained indefinitely when empty.
+	RetentionPolicy string `json:"retention_policy,omitempty"`
+	// MeasurementSchemas declares the columns of each measurement written to the bucket.
+	// They are only applied to buckets with an explicit schema type. Columns may be
+	// added to an existing measurement, but never removed or changed.
+	MeasurementSchemas []MeasurementSchema `json:"measurementSchemas,omitempty"`
+	// ShardGroupDuration is the duration of each shard group within the bucket (e.g. 24h).
+	// The Influx instance chooses a duration based on the retention policy when empty.
+	ShardGroupDuration string `json:"shard_group_duration,omitempty"`
+
+	// Labels are the names of the Labels, within the same namespace and
+	// organization, which are assigned to the bucket in each target instance.
+	// Labels assigned by other means, such as the Influx UI, are left in place.
+	Labels []string `json:"labels,omitempty"`
+
+	// DeletionPolicy determines whether the bucket is removed from
+	// each target Influx instance when this resource is deleted.
+	//+kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
-//+kubebuilder:validation:default=implicit
 //+kubebuilder:validation:Enum=implicit;explicit
 
 type SchemaType string
 
+const (
+	SchemaTypeImplicit SchemaType = "implicit"
+	SchemaTypeExplicit SchemaType = "explicit"
+)
+
+// MeasurementSchema declares the columns of a single measurement within
+// an explicit-schema bucket.
+type MeasurementSchema struct {
+	// Name is the name of the measurement.
+	Name string `json:"name"`
+	// Columns are the tag, field and timestamp columns of the measurement.
+	//+kubebuilder:validation:MinItems=1
+	Columns []MeasurementSchemaColumn `json:"columns"`
+}
+
+// MeasurementSchemaColumn declares a single column of a measurement.
+type MeasurementSchemaColumn struct {
+	// Name is the name of the column.
+	Name string `json:"name"`
+	// Type is the semantic type of the column.
+	Type ColumnSemanticType `json:"type"`
+	// DataType is the data type of a field column, and must be empty otherwise.
+	DataType ColumnDataType `json:"dataType,omitempty"`
+}
+
+//+kubebuilder:validation:Enum=timestamp;tag;field
+
+type ColumnSemanticType string
+
+const (
+	ColumnSemanticTypeTimestamp ColumnSemanticType = "timestamp"
+	ColumnSemanticTypeTag       ColumnSemanticType = "tag"
+	ColumnSemanticTypeField     ColumnSemanticType = "field"
+)
+
+//+kubebuilder:validation:Enum=integer;float;boolean;string;unsigned
+
+type ColumnDataType string
+
 // BucketStatus defines the observed state of Bucket
 type BucketStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the bucket.
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
 	Instances Instances `json:"instances"`
+	// LabelMappings records the identifiers of the labels assigned to the bucket
+	// by the operator in each target instance. Only these are removed when no
+	// longer declared.
+	LabelMappings InstanceLabels `json:"labelMappings,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //*kubebuilder:printcolumn:JSONPath=".spec.organization",name=Organization,type=string
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Bucket is the Schema for the buckets API
 type Bucket struct {
@@ -0,0 +1,158 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var bucketlog = logf.Log.WithName("bucket-resource")
+
+func (r *Bucket) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-paradox-macro-re-v1alpha1-bucket,mutating=true,failurePolicy=fail,sideEffects=None,groups=paradox.macro.re,resources=buckets,verbs=create;update,versions=v1alpha1,name=mbucket.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &Bucket{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *Bucket) Default() {
+	bucketlog.Info("default", "name", r.Name)
+
+	if r.Spec.SchemaType == "" {
+		r.Spec.SchemaType = SchemaTypeImplicit
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-paradox-macro-re-v1alpha1-bucket,mutating=false,failurePolicy=fail,sideEffects=None,groups=paradox.macro.re,resources=buckets,verbs=create;update,versions=v1alpha1,name=vbucket.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Bucket{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Bucket) ValidateCreate() error {
+	bucketlog.Info("validate create", "name", r.Name)
+
+	return invalid("Bucket", r.Name, r.validate())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Bucket) ValidateUpdate(old runtime.Object) error {
+	bucketlog.Info("validate update", "name", r.Name)
+
+	errs := validateUpdate(r, old, func(b *Bucket) interface{} { return b.Spec })
+
+	// the schema type of a bucket is fixed when it is created
+	if previous, ok := old.(*Bucket); ok && previous.Spec.schemaType() != r.Spec.schemaType() {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "schema_type"), "cannot be changed"))
+	}
+
+	return invalid("Bucket", r.Name, errs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Bucket) ValidateDelete() error {
+	return nil
+}
+
+func (r *Bucket) validate() (errs field.ErrorList) {
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateRequired(path.Child("name"), r.Spec.Name)...)
+	errs = append(errs, validateRequired(path.Child("organization"), r.Spec.Organization)...)
+	errs = append(errs, validateLabels(path.Child("labels"), r.Spec.Labels)...)
+	errs = append(errs, validateDuration(path.Child("retention_policy"), r.Spec.RetentionPolicy)...)
+	errs = append(errs, validateDuration(path.Child("shard_group_duration"), r.Spec.ShardGroupDuration)...)
+
+	schemasPath := path.Child("measurementSchemas")
+	if len(r.Spec.MeasurementSchemas) > 0 && r.Spec.schemaType() != SchemaTypeExplicit {
+		errs = append(errs, field.Forbidden(schemasPath, "requires an explicit schema type"))
+	}
+
+	measurements := map[string]struct{}{}
+	for i, schema := range r.Spec.MeasurementSchemas {
+		schemaPath := schemasPath.Index(i)
+		errs = append(errs, validateRequired(schemaPath.Child("name"), schema.Name)...)
+
+		if _, ok := measurements[schema.Name]; ok {
+			errs = append(errs, field.Duplicate(schemaPath.Child("name"), schema.Name))
+		}
+
+		measurements[schema.Name] = struct{}{}
+
+		errs = append(errs, validateMeasurementColumns(schemaPath.Child("columns"), schema.Columns)...)
+	}
+
+	return errs
+}
+
+// validateMeasurementColumns checks that columns have unique names, a single
+// timestamp column, and a data type for field columns alone.
+func validateMeasurementColumns(path *field.Path, columns []MeasurementSchemaColumn) (errs field.ErrorList) {
+	var (
+		names      = map[string]struct{}{}
+		timestamps int
+	)
+
+	for i, column := range columns {
+		columnPath := path.Index(i)
+		errs = append(errs, validateRequired(columnPath.Child("name"), column.Name)...)
+
+		if _, ok := names[column.Name]; ok {
+			errs = append(errs, field.Duplicate(columnPath.Child("name"), column.Name))
+		}
+
+		names[column.Name] = struct{}{}
+
+		switch column.Type {
+		case ColumnSemanticTypeField:
+			if column.DataType == "" {
+				errs = append(errs, field.Required(columnPath.Child("dataType"), "required for field columns"))
+			}
+		case ColumnSemanticTypeTimestamp:
+			timestamps++
+
+			fallthrough
+		default:
+			if column.DataType != "" {
+				errs = append(errs, field.Forbidden(columnPath.Child("dataType"), "only permitted for field columns"))
+			}
+		}
+	}
+
+	if timestamps != 1 {
+		errs = append(errs, field.Invalid(path, timestamps, "must contain exactly one timestamp column"))
+	}
+
+	return errs
+}
+
+// schemaType returns the schema type of the bucket, where empty means implicit.
+func (s BucketSpec) schemaType() SchemaType {
+	if s.SchemaType == "" {
+		return SchemaTypeImplicit
+	}
+
+	return s.SchemaType
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestBucketValidate(t *testing.T) {
+	columns := []MeasurementSchemaColumn{
+		{Name: "time", Type: ColumnSemanticTypeTimestamp},
+		{Name: "usage", Type: ColumnSemanticTypeField, DataType: "float"},
+	}
+
+	tests := []struct {
+		name string
+		spec BucketSpec
+		want []string
+	}{
+		{
+			name: "valid",
+			spec: BucketSpec{Name: "metrics", Organization: "macro", RetentionPolicy: "720h"},
+		},
+		{
+			name: "required fields",
+			want: []string{"FieldValueRequired spec.name", "FieldValueRequired spec.organization"},
+		},
+		{
+			name: "invalid durations",
+			spec: BucketSpec{Name: "metrics", Organization: "macro", RetentionPolicy: "30d", ShardGroupDuration: "-1h"},
+			want: []string{"FieldValueInvalid spec.retention_policy", "FieldValueInvalid spec.shard_group_duration"},
+		},
+		{
+			name: "explicit schemas",
+			spec: BucketSpec{
+				Name: "metrics", Organization: "macro", SchemaType: SchemaTypeExplicit,
+				MeasurementSchemas: []MeasurementSchema{{Name: "cpu", Columns: columns}},
+			},
+		},
+		{
+			name: "schemas of an implicit bucket",
+			spec: BucketSpec{
+				Name: "metrics", Organization: "macro",
+				MeasurementSchemas: []MeasurementSchema{{Name: "cpu", Columns: columns}},
+			},
+			want: []string{"FieldValueForbidden spec.measurementSchemas"},
+		},
+		{
+			name: "duplicate measurements",
+			spec: BucketSpec{
+				Name: "metrics", Organization: "macro", SchemaType: SchemaTypeExplicit,
+				MeasurementSchemas: []MeasurementSchema{{Name: "cpu", Columns: columns}, {Name: "cpu", Columns: columns}},
+			},
+			want: []string{"FieldValueDuplicate spec.measurementSchemas[1].name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := &Bucket{Spec: tt.spec}
+
+			got := errorFields(bucket.validate())
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMeasurementColumns(t *testing.T) {
+	var (
+		timestamp = MeasurementSchemaColumn{Name: "time", Type: ColumnSemanticTypeTimestamp}
+		host      = MeasurementSchemaColumn{Name: "host", Type: ColumnSemanticTypeTag}
+		usage     = MeasurementSchemaColumn{Name: "usage", Type: ColumnSemanticTypeField, DataType: "float"}
+	)
+
+	tests := []struct {
+		name    string
+		columns []MeasurementSchemaColumn
+		want    []string
+	}{
+		{
+			name:    "valid",
+			columns: []MeasurementSchemaColumn{timestamp, host, usage},
+		},
+		{
+			name:    "no timestamp",
+			columns: []MeasurementSchemaColumn{host, usage},
+			want:    []string{"FieldValueInvalid columns"},
+		},
+		{
+			name:    "several timestamps",
+			columns: []MeasurementSchemaColumn{timestamp, {Name: "created", Type: ColumnSemanticTypeTimestamp}},
+			want:    []string{"FieldValueInvalid columns"},
+		},
+		{
+			name:    "duplicate names",
+			columns: []MeasurementSchemaColumn{timestamp, host, {Name: "host", Type: ColumnSemanticTypeTag}},
+			want:    []string{"FieldValueDuplicate columns[2].name"},
+		},
+		{
+			name:    "unnamed column",
+			columns: []MeasurementSchemaColumn{timestamp, {Type: ColumnSemanticTypeTag}},
+			want:    []string{"FieldValueRequired columns[1].name"},
+		},
+		{
+			name:    "field without data type",
+			columns: []MeasurementSchemaColumn{timestamp, {Name: "usage", Type: ColumnSemanticTypeField}},
+			want:    []string{"FieldValueRequired columns[1].dataType"},
+		},
+		{
+			name: "data type of tag and timestamp",
+			columns: []MeasurementSchemaColumn{
+				{Name: "time", Type: ColumnSemanticTypeTimestamp, DataType: "integer"},
+				{Name: "host", Type: ColumnSemanticTypeTag, DataType: "string"},
+			},
+			want: []string{"FieldValueForbidden columns[0].dataType", "FieldValueForbidden columns[1].dataType"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errorFields(validateMeasurementColumns(field.NewPath("columns"), tt.columns))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateMeasurementColumns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBucketValidateUpdate(t *testing.T) {
+	bucket := func(schemaType SchemaType) *Bucket {
+		return &Bucket{Spec: BucketSpec{Name: "metrics", Organization: "macro", SchemaType: schemaType}}
+	}
+
+	tests := []struct {
+		name     string
+		previous *Bucket
+		bucket   *Bucket
+		wantErr  bool
+	}{
+		{name: "unchanged", previous: bucket(SchemaTypeExplicit), bucket: bucket(SchemaTypeExplicit)},
+		{name: "empty schema type is implicit", previous: bucket(""), bucket: bucket(SchemaTypeImplicit)},
+		{name: "schema type changed", previous: bucket(SchemaTypeImplicit), bucket: bucket(SchemaTypeExplicit), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.bucket.ValidateUpdate(tt.previous)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && !apierrors.IsInvalid(err) {
+				t.Errorf("ValidateUpdate() error = %v, want an invalid error", err)
+			}
+		})
+	}
+}
+
+func TestBucketDefault(t *testing.T) {
+	bucket := &Bucket{}
+	bucket.Default()
+
+	if bucket.Spec.SchemaType != SchemaTypeImplicit {
+		t.Errorf("Default() schema type = %q, want %q", bucket.Spec.SchemaType, SchemaTypeImplicit)
+	}
+
+	bucket = &Bucket{Spec: BucketSpec{SchemaType: SchemaTypeExplicit}}
+	bucket.Default()
+
+	if bucket.Spec.SchemaType != SchemaTypeExplicit {
+		t.Errorf("Default() schema type = %q, want %q", bucket.Spec.SchemaType, SchemaTypeExplicit)
+	}
+}
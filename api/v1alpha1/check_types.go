@@ -0,0 +1,189 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CheckSpec defines the desired state of Check. Exactly one of threshold
+// and deadman must be set, which determines the type of the check.
+type CheckSpec struct {
+	// Name is the name of the check in the target Influx instance.
+	Name string `json:"name"`
+	// Organization is the parent organization which owns this check
+	// within the target InfluxData instance.
+	Organization string `json:"organization"`
+	// Description is a string which describes any useful details
+	// regarding the purpose of the check.
+	Description string `json:"description,omitempty"`
+	// Status determines whether the check is scheduled to run.
+	//+kubebuilder:default=active
+	Status ActivityStatus `json:"status,omitempty"`
+	// Query is the Flux query whose results are checked.
+	Query string `json:"query"`
+	// Every is the interval at which the check runs, as a Flux duration (e.g. 1m).
+	Every string `json:"every"`
+	// Offset delays the execution of each run, as a Flux duration (e.g. 30s).
+	Offset string `json:"offset,omitempty"`
+	// StatusMessageTemplate is the template of the message of each status written by the check.
+	StatusMessageTemplate string `json:"statusMessageTemplate,omitempty"`
+	// Tags are added to each status written by the check.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Threshold assigns a level to each result by comparing its value with thresholds.
+	Threshold *ThresholdCheck `json:"threshold,omitempty"`
+	// Deadman assigns a level to each series which has stopped reporting.
+	Deadman *DeadmanCheck `json:"deadman,omitempty"`
+
+	// Labels are the names of the Labels, within the same namespace and
+	// organization, which are assigned to the check in each target instance.
+	// Labels assigned by other means, such as the Influx UI, are left in place.
+	Labels []string `json:"labels,omitempty"`
+
+	// DeletionPolicy determines whether the check is removed from
+	// each target Influx instance when this resource is deleted.
+	//+kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// Type returns the type of the check, or an empty type when no check is defined.
+func (s CheckSpec) Type() CheckType {
+	switch {
+	case s.Threshold != nil:
+		return CheckTypeThreshold
+	case s.Deadman != nil:
+		return CheckTypeDeadman
+	default:
+		return ""
+	}
+}
+
+// CheckType is the type of a check.
+type CheckType string
+
+const (
+	CheckTypeThreshold = CheckType("threshold")
+	CheckTypeDeadman   = CheckType("deadman")
+)
+
+//+kubebuilder:validation:Enum=CRIT;WARN;INFO;OK
+
+// CheckLevel is the level of a status written by a check.
+type CheckLevel string
+
+const (
+	CheckLevelCrit = CheckLevel("CRIT")
+	CheckLevelWarn = CheckLevel("WARN")
+	CheckLevelInfo = CheckLevel("INFO")
+	CheckLevelOK   = CheckLevel("OK")
+)
+
+// ThresholdCheck defines the thresholds of a threshold check.
+type ThresholdCheck struct {
+	// Thresholds are the thresholds against which each value is compared.
+	//+kubebuilder:validation:MinItems=1
+	Thresholds []Threshold `json:"thresholds"`
+}
+
+// Threshold assigns a level to values which cross it.
+type Threshold struct {
+	// Level is the level of the status written for values which cross the threshold.
+	Level CheckLevel `json:"level"`
+	// Type determines how values are compared with the threshold.
+	Type ThresholdType `json:"type"`
+	// Value is the decimal value which greater and lesser thresholds compare against.
+	Value string `json:"value,omitempty"`
+	// Min is the decimal lower bound of a range threshold.
+	Min string `json:"min,omitempty"`
+	// Max is the decimal upper bound of a range threshold.
+	Max string `json:"max,omitempty"`
+	// Within determines whether a range threshold is crossed by values
+	// within the range, rather than outside of it.
+	Within bool `json:"within,omitempty"`
+	// AllValues determines whether every value within the window must cross the threshold.
+	AllValues bool `json:"allValues,omitempty"`
+}
+
+//+kubebuilder:validation:Enum=greater;lesser;range
+
+type ThresholdType string
+
+const (
+	ThresholdTypeGreater = ThresholdType("greater")
+	ThresholdTypeLesser  = ThresholdType("lesser")
+	ThresholdTypeRange   = ThresholdType("range")
+)
+
+// DeadmanCheck defines when a series is considered to have stopped reporting.
+type DeadmanCheck struct {
+	// TimeSince is the duration without data after which the level is assigned (e.g. 90s).
+	TimeSince string `json:"timeSince"`
+	// StaleTime is the duration after which a series which has stopped
+	// reporting is no longer checked (e.g. 10m).
+	StaleTime string `json:"staleTime,omitempty"`
+	// ReportZero determines whether a status is written for series without data.
+	ReportZero bool `json:"reportZero,omitempty"`
+	// Level is the level of the status written for series which have stopped reporting.
+	//+kubebuilder:default=CRIT
+	Level CheckLevel `json:"level,omitempty"`
+}
+
+// CheckStatus defines the observed state of Check
+type CheckStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the check.
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	Instances Instances `json:"instances"`
+	// LabelMappings records the identifiers of the labels assigned to the check
+	// by the operator in each target instance. Only these are removed when no
+	// longer declared.
+	LabelMappings InstanceLabels `json:"labelMappings,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Organization",type=string,JSONPath=`.spec.organization`
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.spec.status`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Check is the Schema for the checks API
+type Check struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CheckSpec   `json:"spec,omitempty"`
+	Status CheckStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CheckList contains a list of Check
+type CheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Check `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Check{}, &CheckList{})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var checklog = logf.Log.WithName("check-resource")
+
+func (r *Check) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-paradox-macro-re-v1alpha1-check,mutating=false,failurePolicy=fail,sideEffects=None,groups=paradox.macro.re,resources=checks,verbs=create;update,versions=v1alpha1,name=vcheck.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Check{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Check) ValidateCreate() error {
+	checklog.Info("validate create", "name", r.Name)
+
+	return invalid("Check", r.Name, r.validate())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Check) ValidateUpdate(old runtime.Object) error {
+	checklog.Info("validate update", "name", r.Name)
+
+	return invalid("Check", r.Name, validateUpdate(r, old, func(c *Check) interface{} { return c.Spec }))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Check) ValidateDelete() error {
+	return nil
+}
+
+func (r *Check) validate() (errs field.ErrorList) {
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateRequired(path.Child("name"), r.Spec.Name)...)
+	errs = append(errs, validateRequired(path.Child("organization"), r.Spec.Organization)...)
+	errs = append(errs, validateLabels(path.Child("labels"), r.Spec.Labels)...)
+	errs = append(errs, validateRequired(path.Child("query"), r.Spec.Query)...)
+	errs = append(errs, validateRequired(path.Child("every"), r.Spec.Every)...)
+	errs = append(errs, validateFluxDuration(path.Child("every"), r.Spec.Every)...)
+	errs = append(errs, validateFluxDuration(path.Child("offset"), r.Spec.Offset)...)
+
+	errs = append(errs, validateExactlyOne(path, []string{"threshold", "deadman"}, []bool{r.Spec.Threshold != nil, r.Spec.Deadman != nil})...)
+
+	if threshold := r.Spec.Threshold; threshold != nil {
+		thresholdsPath := path.Child("threshold", "thresholds")
+		if len(threshold.Thresholds) == 0 {
+			errs = append(errs, field.Required(thresholdsPath, ""))
+		}
+
+		for i, t := range threshold.Thresholds {
+			errs = append(errs, validateThreshold(thresholdsPath.Index(i), t)...)
+		}
+	}
+
+	if deadman := r.Spec.Deadman; deadman != nil {
+		deadmanPath := path.Child("deadman")
+		errs = append(errs, validateRequired(deadmanPath.Child("timeSince"), deadman.TimeSince)...)
+		errs = append(errs, validateFluxDuration(deadmanPath.Child("timeSince"), deadman.TimeSince)...)
+		errs = append(errs, validateFluxDuration(deadmanPath.Child("staleTime"), deadman.StaleTime)...)
+	}
+
+	return errs
+}
+
+// validateThreshold checks that threshold declares the values its type compares against.
+func validateThreshold(path *field.Path, threshold Threshold) (errs field.ErrorList) {
+	if threshold.Type != ThresholdTypeRange {
+		errs = append(errs, validateDecimal(path.Child("value"), threshold.Value)...)
+
+		if threshold.Min != "" {
+			errs = append(errs, field.Forbidden(path.Child("min"), "only permitted when type is range"))
+		}
+
+		if threshold.Max != "" {
+			errs = append(errs, field.Forbidden(path.Child("max"), "only permitted when type is range"))
+		}
+
+		return errs
+	}
+
+	if threshold.Value != "" {
+		errs = append(errs, field.Forbidden(path.Child("value"), "not permitted when type is range"))
+	}
+
+	minErrs := validateDecimal(path.Child("min"), threshold.Min)
+	maxErrs := validateDecimal(path.Child("max"), threshold.Max)
+	errs = append(append(errs, minErrs...), maxErrs...)
+
+	if len(minErrs) == 0 && len(maxErrs) == 0 {
+		min, _ := strconv.ParseFloat(threshold.Min, 64)
+		max, _ := strconv.ParseFloat(threshold.Max, 64)
+		if min > max {
+			errs = append(errs, field.Invalid(path.Child("max"), threshold.Max, "must not be less than min"))
+		}
+	}
+
+	return errs
+}
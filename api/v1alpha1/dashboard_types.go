@@ -0,0 +1,134 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DashboardSpec defines the desired state of Dashboard. The cells of the
+// dashboard are declared by exactly one of cells and configMapRef.
+type DashboardSpec struct {
+	// Name is the name of the dashboard in the target Influx instance.
+	Name string `json:"name"`
+	// Organization is the parent organization which owns this dashboard
+	// within the target InfluxData instance.
+	Organization string `json:"organization"`
+	// Description is a string which describes any useful details
+	// regarding the purpose of the dashboard.
+	Description string `json:"description,omitempty"`
+
+	// Cells are the cells of the dashboard, along with their queries.
+	Cells []DashboardCell `json:"cells,omitempty"`
+	// ConfigMapRef identifies a key of a ConfigMap holding a dashboard exported
+	// as JSON from the Influx UI, whose cells are reproduced in each instance.
+	// The name and description of the export are replaced by those of the spec.
+	ConfigMapRef *ConfigMapRef `json:"configMapRef,omitempty"`
+
+	// Labels are the names of the Labels, within the same namespace and
+	// organization, which are assigned to the dashboard in each target instance.
+	// Labels assigned by other means, such as the Influx UI, are left in place.
+	Labels []string `json:"labels,omitempty"`
+
+	// DeletionPolicy determines whether the dashboard is removed from
+	// each target Influx instance when this resource is deleted.
+	//+kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// DashboardCell is a single visualization placed on the grid of a dashboard.
+type DashboardCell struct {
+	// Name is the name of the cell, which is displayed as its title.
+	Name string `json:"name"`
+	// X is the column of the grid in which the cell starts.
+	//+kubebuilder:validation:Minimum=0
+	X int32 `json:"x"`
+	// Y is the row of the grid in which the cell starts.
+	//+kubebuilder:validation:Minimum=0
+	Y int32 `json:"y"`
+	// W is the number of columns spanned by the cell.
+	//+kubebuilder:validation:Minimum=1
+	W int32 `json:"w"`
+	// H is the number of rows spanned by the cell.
+	//+kubebuilder:validation:Minimum=1
+	H int32 `json:"h"`
+	// Type is the type of visualization of the cell.
+	//+kubebuilder:default=xy
+	Type DashboardCellType `json:"type,omitempty"`
+	// Queries are the Flux queries whose results are visualized.
+	// Markdown cells have no queries.
+	Queries []string `json:"queries,omitempty"`
+	// Note is the Markdown text of markdown cells, or else a note which is
+	// displayed alongside the visualization.
+	Note string `json:"note,omitempty"`
+}
+
+//+kubebuilder:validation:Enum=xy;single-stat;table;markdown
+
+// DashboardCellType is the type of visualization of a dashboard cell.
+type DashboardCellType string
+
+const (
+	DashboardCellTypeXY         = DashboardCellType("xy")
+	DashboardCellTypeSingleStat = DashboardCellType("single-stat")
+	DashboardCellTypeTable      = DashboardCellType("table")
+	DashboardCellTypeMarkdown   = DashboardCellType("markdown")
+)
+
+// DashboardStatus defines the observed state of Dashboard
+type DashboardStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the dashboard.
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	Instances Instances `json:"instances"`
+	// LabelMappings records the identifiers of the labels assigned to the dashboard
+	// by the operator in each target instance. Only these are removed when no
+	// longer declared.
+	LabelMappings InstanceLabels `json:"labelMappings,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Organization",type=string,JSONPath=`.spec.organization`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Dashboard is the Schema for the dashboards API
+type Dashboard struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DashboardSpec   `json:"spec,omitempty"`
+	Status DashboardStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DashboardList contains a list of Dashboard
+type DashboardList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Dashboard `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Dashboard{}, &DashboardList{})
+}
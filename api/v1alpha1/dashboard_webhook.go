@@ -0,0 +1,104 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var dashboardlog = logf.Log.WithName("dashboard-resource")
+
+func (r *Dashboard) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-paradox-macro-re-v1alpha1-dashboard,mutating=false,failurePolicy=fail,sideEffects=None,groups=paradox.macro.re,resources=dashboards,verbs=create;update,versions=v1alpha1,name=vdashboard.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Dashboard{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Dashboard) ValidateCreate() error {
+	dashboardlog.Info("validate create", "name", r.Name)
+
+	return invalid("Dashboard", r.Name, r.validate())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Dashboard) ValidateUpdate(old runtime.Object) error {
+	dashboardlog.Info("validate update", "name", r.Name)
+
+	return invalid("Dashboard", r.Name, validateUpdate(r, old, func(d *Dashboard) interface{} { return d.Spec }))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Dashboard) ValidateDelete() error {
+	return nil
+}
+
+func (r *Dashboard) validate() (errs field.ErrorList) {
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateRequired(path.Child("name"), r.Spec.Name)...)
+	errs = append(errs, validateRequired(path.Child("organization"), r.Spec.Organization)...)
+	errs = append(errs, validateLabels(path.Child("labels"), r.Spec.Labels)...)
+
+	errs = append(errs, validateExactlyOne(path, []string{"cells", "configMapRef"}, []bool{
+		len(r.Spec.Cells) > 0,
+		r.Spec.ConfigMapRef != nil,
+	})...)
+
+	if ref := r.Spec.ConfigMapRef; ref != nil {
+		refPath := path.Child("configMapRef")
+		errs = append(errs, validateRequired(refPath.Child("namespace"), ref.Namespace)...)
+		errs = append(errs, validateRequired(refPath.Child("name"), ref.Name)...)
+		errs = append(errs, validateRequired(refPath.Child("key"), ref.Key)...)
+	}
+
+	// cells are matched with those of each instance by name
+	names := map[string]struct{}{}
+	for i, cell := range r.Spec.Cells {
+		cellPath := path.Child("cells").Index(i)
+
+		if _, ok := names[cell.Name]; ok {
+			errs = append(errs, field.Duplicate(cellPath.Child("name"), cell.Name))
+		}
+
+		names[cell.Name] = struct{}{}
+
+		switch cell.Type {
+		case DashboardCellTypeMarkdown:
+			errs = append(errs, validateRequired(cellPath.Child("note"), cell.Note)...)
+
+			if len(cell.Queries) > 0 {
+				errs = append(errs, field.Forbidden(cellPath.Child("queries"), "not permitted when type is markdown"))
+			}
+		default:
+			if len(cell.Queries) == 0 {
+				errs = append(errs, field.Required(cellPath.Child("queries"), "at least one query is required"))
+			}
+		}
+	}
+
+	return errs
+}
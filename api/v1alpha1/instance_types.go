@@ -23,15 +23,118 @@ import (
 // InstanceSpec defines the desired state of Instance
 type InstanceSpec struct {
 	Address string `json:"address"`
-	// Authorization will support Organization provisioning in the future.
-	// Authorization *SecretRef `json:"authorization"`
+	// TLS configures the verification of the certificate presented by the instance,
+	// and the certificate presented to it.
+	TLS *InstanceTLS `json:"tls,omitempty"`
+	// Proxy is the URL of an HTTP proxy through which requests are sent.
+	// The proxy is taken from the environment of the operator when empty.
+	Proxy string `json:"proxy,omitempty"`
+	// Timeout is the timeout of each request sent to the instance (e.g. 30s).
+	// It defaults to 20s.
+	Timeout string `json:"timeout,omitempty"`
+	// Headers is a set of headers added to every request sent to the instance.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Authorization is an operator (or all-access) credential for the instance.
+	// It is used to provision resources, such as organizations, which cannot be
+	// created using organization scoped credentials.
+	Authorization *InstanceAuthorization `json:"authorization,omitempty"`
+	// Onboarding configures the initial setup which is performed against fresh
+	// InfluxDB OSS instances reporting that they have not yet been set up.
+	Onboarding *InstanceOnboarding `json:"onboarding,omitempty"`
+}
+
+// InstanceTLS configures TLS connections to an instance.
+type InstanceTLS struct {
+	// CASecretRef identifies a PEM encoded bundle of certificate authorities within
+	// a Secret, which is used to verify the instance in place of the system roots.
+	CASecretRef *SecretRef `json:"caSecretRef,omitempty"`
+	// CAConfigMapRef identifies a PEM encoded bundle of certificate authorities within
+	// a ConfigMap, which is used to verify the instance in place of the system roots.
+	CAConfigMapRef *ConfigMapRef `json:"caConfigMapRef,omitempty"`
+	// ClientCertificateSecretRef identifies a Secret of type kubernetes.io/tls holding
+	// the client certificate (tls.crt) and key (tls.key) presented to the instance.
+	ClientCertificateSecretRef *ObjectRef `json:"clientCertificateSecretRef,omitempty"`
+	// InsecureSkipVerify disables verification of the certificate presented by the instance.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// ServerName is the name used to verify the certificate presented by the instance.
+	// It defaults to the host of the instance address.
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// ConfigMapRef identifies a single key of a ConfigMap.
+type ConfigMapRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+}
+
+// ObjectRef identifies an object by namespace and name.
+type ObjectRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// InstanceOnboarding defines the initial setup of an InfluxDB OSS instance.
+type InstanceOnboarding struct {
+	// Username is the name of the initial user.
+	Username string `json:"username"`
+	// PasswordSecretRef identifies the password of the initial user.
+	PasswordSecretRef SecretRef `json:"passwordSecretRef"`
+	// Organization is the name of the initial organization.
+	Organization string `json:"organization"`
+	// Bucket is the name of the initial bucket.
+	Bucket string `json:"bucket"`
+	// RetentionPeriod is the retention period of the initial bucket (e.g. 72h).
+	// Data in the initial bucket is retained indefinitely when empty.
+	RetentionPeriod string `json:"retentionPeriod,omitempty"`
+	// TokenSecretName is the name of the Secret, within the namespace of the
+	// Instance, in which the resulting operator token is stored.
+	// It defaults to <instance name>-operator-token. The Secret is retained when
+	// the Instance is deleted, as the instance cannot be onboarded again. An existing
+	// Secret of that name is only used when it is labelled for the Instance.
+	TokenSecretName string `json:"tokenSecretName,omitempty"`
 }
 
 // InstanceStatus defines the observed state of Instance
-type InstanceStatus struct{}
+type InstanceStatus struct {
+	// Reachable is true when the instance responded to the most recent probe.
+	Reachable bool `json:"reachable"`
+	// Ready is true when the instance reported itself as ready to serve requests.
+	Ready bool `json:"ready"`
+	// Health is the status reported by the health endpoint of the instance.
+	Health string `json:"health,omitempty"`
+	// Version is the server version reported by the instance.
+	Version string `json:"version,omitempty"`
+	// Build is the flavour of InfluxDB server reported by the instance.
+	Build InstanceBuild `json:"build,omitempty"`
+	// Onboarded is true when the instance has completed initial setup.
+	// It is left unset when the instance does not expose the setup endpoint.
+	Onboarded *bool `json:"onboarded,omitempty"`
+	// Message contains details of the last failed probe.
+	Message string `json:"message,omitempty"`
+	// LastProbeTime is the time at which the instance was last probed.
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
+	// OperatorTokenSecret identifies the Secret holding the operator token
+	// generated when the instance was onboarded.
+	OperatorTokenSecret *SecretRef `json:"operatorTokenSecret,omitempty"`
+}
+
+// InstanceBuild is the flavour of InfluxDB server an instance is running.
+type InstanceBuild string
+
+const (
+	InstanceBuildOSS   = InstanceBuild("OSS")
+	InstanceBuildCloud = InstanceBuild("Cloud")
+)
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Address",type=string,JSONPath=`.spec.address`
+//+kubebuilder:printcolumn:name="Reachable",type=boolean,JSONPath=`.status.reachable`
+//+kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.version`
+//+kubebuilder:printcolumn:name="Build",type=string,JSONPath=`.status.build`
+//+kubebuilder:printcolumn:name="Onboarded",type=boolean,JSONPath=`.status.onboarded`
+//+kubebuilder:printcolumn:name="Last Probe",type=date,JSONPath=`.status.lastProbeTime`
 
 // Instance is the Schema for the instances API
 type Instance struct {
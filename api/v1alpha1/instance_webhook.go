@@ -0,0 +1,114 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var instancelog = logf.Log.WithName("instance-resource")
+
+func (r *Instance) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-paradox-macro-re-v1alpha1-instance,mutating=false,failurePolicy=fail,sideEffects=None,groups=paradox.macro.re,resources=instances,verbs=create;update,versions=v1alpha1,name=vinstance.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Instance{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Instance) ValidateCreate() error {
+	instancelog.Info("validate create", "name", r.Name)
+
+	return invalid("Instance", r.Name, r.validate())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Instance) ValidateUpdate(old runtime.Object) error {
+	instancelog.Info("validate update", "name", r.Name)
+
+	return invalid("Instance", r.Name, validateUpdate(r, old, func(i *Instance) interface{} { return i.Spec }))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Instance) ValidateDelete() error {
+	return nil
+}
+
+func (r *Instance) validate() (errs field.ErrorList) {
+	path := field.NewPath("spec")
+
+	if address, err := url.Parse(r.Spec.Address); err != nil {
+		errs = append(errs, field.Invalid(path.Child("address"), r.Spec.Address, err.Error()))
+	} else if (address.Scheme != "http" && address.Scheme != "https") || address.Host == "" {
+		errs = append(errs, field.Invalid(path.Child("address"), r.Spec.Address, "must be an absolute http or https URL"))
+	}
+
+	errs = append(errs, validateDuration(path.Child("timeout"), r.Spec.Timeout)...)
+
+	if r.Spec.Proxy != "" {
+		if proxy, err := url.Parse(r.Spec.Proxy); err != nil {
+			errs = append(errs, field.Invalid(path.Child("proxy"), r.Spec.Proxy, err.Error()))
+		} else if proxy.Host == "" {
+			errs = append(errs, field.Invalid(path.Child("proxy"), r.Spec.Proxy, "must be an absolute URL"))
+		}
+	}
+
+	if tls := r.Spec.TLS; tls != nil {
+		tlsPath := path.Child("tls")
+		if tls.CASecretRef != nil {
+			errs = append(errs, validateSecretRef(tlsPath.Child("caSecretRef"), *tls.CASecretRef)...)
+		}
+
+		if ref := tls.CAConfigMapRef; ref != nil {
+			refPath := tlsPath.Child("caConfigMapRef")
+			errs = append(errs, validateRequired(refPath.Child("namespace"), ref.Namespace)...)
+			errs = append(errs, validateRequired(refPath.Child("name"), ref.Name)...)
+			errs = append(errs, validateRequired(refPath.Child("key"), ref.Key)...)
+		}
+
+		if ref := tls.ClientCertificateSecretRef; ref != nil {
+			refPath := tlsPath.Child("clientCertificateSecretRef")
+			errs = append(errs, validateRequired(refPath.Child("namespace"), ref.Namespace)...)
+			errs = append(errs, validateRequired(refPath.Child("name"), ref.Name)...)
+		}
+	}
+
+	if r.Spec.Authorization != nil {
+		errs = append(errs, validateInstanceAuthorization(path.Child("authorization"), *r.Spec.Authorization, false)...)
+	}
+
+	if onboarding := r.Spec.Onboarding; onboarding != nil {
+		onboardingPath := path.Child("onboarding")
+		errs = append(errs, validateRequired(onboardingPath.Child("username"), onboarding.Username)...)
+		errs = append(errs, validateRequired(onboardingPath.Child("organization"), onboarding.Organization)...)
+		errs = append(errs, validateRequired(onboardingPath.Child("bucket"), onboarding.Bucket)...)
+		errs = append(errs, validateSecretRef(onboardingPath.Child("passwordSecretRef"), onboarding.PasswordSecretRef)...)
+		errs = append(errs, validateDuration(onboardingPath.Child("retentionPeriod"), onboarding.RetentionPeriod)...)
+	}
+
+	return errs
+}
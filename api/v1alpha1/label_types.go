@@ -0,0 +1,82 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LabelSpec defines the desired state of Label
+type LabelSpec struct {
+	// Name is the name of the label in the target Influx instance.
+	Name string `json:"name"`
+	// Organization is the parent organization which owns this label
+	// within the target InfluxData instance.
+	Organization string `json:"organization"`
+	// Color is the hex color with which the label is displayed (e.g. #326BBA).
+	//+kubebuilder:validation:Pattern=`^#[0-9a-fA-F]{6}$`
+	Color string `json:"color,omitempty"`
+	// Description is a string which describes any useful details
+	// regarding the purpose of the label.
+	Description string `json:"description,omitempty"`
+
+	// DeletionPolicy determines whether the label is removed from
+	// each target Influx instance when this resource is deleted.
+	//+kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// LabelStatus defines the observed state of Label
+type LabelStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the label.
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	Instances Instances `json:"instances"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Organization",type=string,JSONPath=`.spec.organization`
+//+kubebuilder:printcolumn:name="Color",type=string,JSONPath=`.spec.color`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Label is the Schema for the labels API
+type Label struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LabelSpec   `json:"spec,omitempty"`
+	Status LabelStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LabelList contains a list of Label
+type LabelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Label `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Label{}, &LabelList{})
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var labellog = logf.Log.WithName("label-resource")
+
+func (r *Label) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-paradox-macro-re-v1alpha1-label,mutating=false,failurePolicy=fail,sideEffects=None,groups=paradox.macro.re,resources=labels,verbs=create;update,versions=v1alpha1,name=vlabel.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Label{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Label) ValidateCreate() error {
+	labellog.Info("validate create", "name", r.Name)
+
+	return invalid("Label", r.Name, r.validate())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Label) ValidateUpdate(old runtime.Object) error {
+	labellog.Info("validate update", "name", r.Name)
+
+	return invalid("Label", r.Name, validateUpdate(r, old, func(l *Label) interface{} { return l.Spec }))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Label) ValidateDelete() error {
+	return nil
+}
+
+func (r *Label) validate() (errs field.ErrorList) {
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateRequired(path.Child("name"), r.Spec.Name)...)
+	errs = append(errs, validateRequired(path.Child("organization"), r.Spec.Organization)...)
+
+	return errs
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NotificationEndpointSpec defines the desired state of NotificationEndpoint.
+// Exactly one of slack, http and pagerduty must be set, which determines the
+// type of the endpoint.
+type NotificationEndpointSpec struct {
+	// Name is the name of the notification endpoint in the target Influx instance.
+	Name string `json:"name"`
+	// Organization is the parent organization which owns this notification
+	// endpoint within the target InfluxData instance.
+	Organization string `json:"organization"`
+	// Description is a string which describes any useful details
+	// regarding the purpose of the notification endpoint.
+	Description string `json:"description,omitempty"`
+	// Status determines whether notifications are sent to the endpoint.
+	//+kubebuilder:default=active
+	Status ActivityStatus `json:"status,omitempty"`
+
+	// Slack sends notifications to a Slack incoming webhook or the Slack API.
+	Slack *SlackNotificationEndpoint `json:"slack,omitempty"`
+	// HTTP sends notifications to an arbitrary HTTP endpoint.
+	HTTP *HTTPNotificationEndpoint `json:"http,omitempty"`
+	// PagerDuty sends notifications to a PagerDuty service.
+	PagerDuty *PagerDutyNotificationEndpoint `json:"pagerduty,omitempty"`
+
+	// Labels are the names of the Labels, within the same namespace and
+	// organization, which are assigned to the notification endpoint in each target instance.
+	// Labels assigned by other means, such as the Influx UI, are left in place.
+	Labels []string `json:"labels,omitempty"`
+
+	// DeletionPolicy determines whether the notification endpoint is removed
+	// from each target Influx instance when this resource is deleted.
+	//+kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// Type returns the type of the endpoint, or an empty type when no endpoint is defined.
+func (s NotificationEndpointSpec) Type() NotificationEndpointType {
+	switch {
+	case s.Slack != nil:
+		return NotificationEndpointTypeSlack
+	case s.HTTP != nil:
+		return NotificationEndpointTypeHTTP
+	case s.PagerDuty != nil:
+		return NotificationEndpointTypePagerDuty
+	default:
+		return ""
+	}
+}
+
+// SecretRefs returns every Secret key referenced by the endpoint.
+func (s NotificationEndpointSpec) SecretRefs() (refs []SecretRef) {
+	var candidates []*SecretRef
+	switch {
+	case s.Slack != nil:
+		candidates = append(candidates, s.Slack.TokenSecretRef)
+	case s.HTTP != nil:
+		candidates = append(candidates, s.HTTP.UsernameSecretRef, s.HTTP.PasswordSecretRef, s.HTTP.TokenSecretRef)
+	case s.PagerDuty != nil:
+		candidates = append(candidates, &s.PagerDuty.RoutingKeySecretRef)
+	}
+
+	for _, ref := range candidates {
+		if ref != nil {
+			refs = append(refs, *ref)
+		}
+	}
+
+	return refs
+}
+
+// NotificationEndpointType is the type of a notification endpoint.
+type NotificationEndpointType string
+
+const (
+	NotificationEndpointTypeSlack     = NotificationEndpointType("slack")
+	NotificationEndpointTypeHTTP      = NotificationEndpointType("http")
+	NotificationEndpointTypePagerDuty = NotificationEndpointType("pagerduty")
+)
+
+// SlackNotificationEndpoint defines an endpoint which posts to Slack.
+type SlackNotificationEndpoint struct {
+	// URL is the Slack incoming webhook URL, or the Slack API URL when a token is supplied.
+	URL string `json:"url,omitempty"`
+	// TokenSecretRef identifies the Slack API token within a Secret.
+	TokenSecretRef *SecretRef `json:"tokenSecretRef,omitempty"`
+}
+
+// HTTPNotificationEndpoint defines an endpoint which sends each notification
+// as an HTTP request.
+type HTTPNotificationEndpoint struct {
+	// URL is the URL to which notifications are sent.
+	URL string `json:"url"`
+	// Method is the HTTP method of each request.
+	//+kubebuilder:default=POST
+	//+kubebuilder:validation:Enum=POST;GET;PUT
+	Method string `json:"method,omitempty"`
+	// AuthMethod is the method by which requests are authenticated.
+	//+kubebuilder:default=none
+	AuthMethod HTTPAuthMethod `json:"authMethod,omitempty"`
+	// UsernameSecretRef identifies the username of basic authentication within a Secret.
+	UsernameSecretRef *SecretRef `json:"usernameSecretRef,omitempty"`
+	// PasswordSecretRef identifies the password of basic authentication within a Secret.
+	PasswordSecretRef *SecretRef `json:"passwordSecretRef,omitempty"`
+	// TokenSecretRef identifies the token of bearer authentication within a Secret.
+	TokenSecretRef *SecretRef `json:"tokenSecretRef,omitempty"`
+	// Headers are added to each request.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+//+kubebuilder:validation:Enum=none;basic;bearer
+
+type HTTPAuthMethod string
+
+const (
+	HTTPAuthMethodNone   = HTTPAuthMethod("none")
+	HTTPAuthMethodBasic  = HTTPAuthMethod("basic")
+	HTTPAuthMethodBearer = HTTPAuthMethod("bearer")
+)
+
+// PagerDutyNotificationEndpoint defines an endpoint which triggers PagerDuty events.
+type PagerDutyNotificationEndpoint struct {
+	// ClientURL is the URL linked to from each PagerDuty event.
+	ClientURL string `json:"clientURL,omitempty"`
+	// RoutingKeySecretRef identifies the integration key of the PagerDuty service within a Secret.
+	RoutingKeySecretRef SecretRef `json:"routingKeySecretRef"`
+}
+
+// NotificationEndpointStatus defines the observed state of NotificationEndpoint
+type NotificationEndpointStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the notification endpoint.
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	Instances Instances `json:"instances"`
+	// SecretsHashes identifies the content of the secrets last written to the
+	// notification endpoint in each target instance. Influx does not return
+	// secrets, so the endpoint is rewritten whenever they change.
+	SecretsHashes InstanceHashes `json:"secretsHashes,omitempty"`
+	// LabelMappings records the identifiers of the labels assigned to the notification endpoint
+	// by the operator in each target instance. Only these are removed when no
+	// longer declared.
+	LabelMappings InstanceLabels `json:"labelMappings,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Organization",type=string,JSONPath=`.spec.organization`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NotificationEndpoint is the Schema for the notificationendpoints API
+type NotificationEndpoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotificationEndpointSpec   `json:"spec,omitempty"`
+	Status NotificationEndpointStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NotificationEndpointList contains a list of NotificationEndpoint
+type NotificationEndpointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NotificationEndpoint `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NotificationEndpoint{}, &NotificationEndpointList{})
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var notificationendpointlog = logf.Log.WithName("notificationendpoint-resource")
+
+func (r *NotificationEndpoint) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-paradox-macro-re-v1alpha1-notificationendpoint,mutating=false,failurePolicy=fail,sideEffects=None,groups=paradox.macro.re,resources=notificationendpoints,verbs=create;update,versions=v1alpha1,name=vnotificationendpoint.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &NotificationEndpoint{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *NotificationEndpoint) ValidateCreate() error {
+	notificationendpointlog.Info("validate create", "name", r.Name)
+
+	return invalid("NotificationEndpoint", r.Name, r.validate())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *NotificationEndpoint) ValidateUpdate(old runtime.Object) error {
+	notificationendpointlog.Info("validate update", "name", r.Name)
+
+	return invalid("NotificationEndpoint", r.Name, validateUpdate(r, old, func(n *NotificationEndpoint) interface{} { return n.Spec }))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *NotificationEndpoint) ValidateDelete() error {
+	return nil
+}
+
+func (r *NotificationEndpoint) validate() (errs field.ErrorList) {
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateRequired(path.Child("name"), r.Spec.Name)...)
+	errs = append(errs, validateRequired(path.Child("organization"), r.Spec.Organization)...)
+	errs = append(errs, validateLabels(path.Child("labels"), r.Spec.Labels)...)
+
+	errs = append(errs, validateExactlyOne(path, []string{"slack", "http", "pagerduty"}, []bool{
+		r.Spec.Slack != nil,
+		r.Spec.HTTP != nil,
+		r.Spec.PagerDuty != nil,
+	})...)
+
+	if slack := r.Spec.Slack; slack != nil {
+		slackPath := path.Child("slack")
+		if slack.TokenSecretRef == nil {
+			errs = append(errs, validateRequired(slackPath.Child("url"), slack.URL)...)
+		} else {
+			errs = append(errs, validateSecretRef(slackPath.Child("tokenSecretRef"), *slack.TokenSecretRef)...)
+		}
+	}
+
+	if http := r.Spec.HTTP; http != nil {
+		errs = append(errs, validateHTTPNotificationEndpoint(path.Child("http"), *http)...)
+	}
+
+	if pagerDuty := r.Spec.PagerDuty; pagerDuty != nil {
+		errs = append(errs, validateSecretRef(path.Child("pagerduty", "routingKeySecretRef"), pagerDuty.RoutingKeySecretRef)...)
+	}
+
+	return errs
+}
+
+// validateHTTPNotificationEndpoint checks that endpoint references the secrets
+// its authentication method requires, and no other.
+func validateHTTPNotificationEndpoint(path *field.Path, endpoint HTTPNotificationEndpoint) (errs field.ErrorList) {
+	errs = append(errs, validateRequired(path.Child("url"), endpoint.URL)...)
+
+	refs := []struct {
+		name    string
+		ref     *SecretRef
+		allowed bool
+	}{
+		{"usernameSecretRef", endpoint.UsernameSecretRef, endpoint.AuthMethod == HTTPAuthMethodBasic},
+		{"passwordSecretRef", endpoint.PasswordSecretRef, endpoint.AuthMethod == HTTPAuthMethodBasic},
+		{"tokenSecretRef", endpoint.TokenSecretRef, endpoint.AuthMethod == HTTPAuthMethodBearer},
+	}
+
+	for _, ref := range refs {
+		switch {
+		case ref.allowed && ref.ref == nil:
+			errs = append(errs, field.Required(path.Child(ref.name), "required when authMethod is "+string(endpoint.AuthMethod)))
+		case ref.allowed:
+			errs = append(errs, validateSecretRef(path.Child(ref.name), *ref.ref)...)
+		case ref.ref != nil:
+			errs = append(errs, field.Forbidden(path.Child(ref.name), "not permitted when authMethod is "+string(endpoint.AuthMethod)))
+		}
+	}
+
+	return errs
+}
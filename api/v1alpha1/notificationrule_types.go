@@ -0,0 +1,141 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NotificationRuleSpec defines the desired state of NotificationRule
+type NotificationRuleSpec struct {
+	// Name is the name of the notification rule in the target Influx instance.
+	Name string `json:"name"`
+	// Organization is the parent organization which owns this notification
+	// rule within the target InfluxData instance.
+	Organization string `json:"organization"`
+	// Description is a string which describes any useful details
+	// regarding the purpose of the notification rule.
+	Description string `json:"description,omitempty"`
+	// Status determines whether the notification rule is scheduled to run.
+	//+kubebuilder:default=active
+	Status ActivityStatus `json:"status,omitempty"`
+	// Endpoint is the name of the NotificationEndpoint, within the same namespace,
+	// to which notifications are sent. The type of the rule follows that of the endpoint.
+	Endpoint string `json:"endpoint"`
+	// Check is the name of a Check, within the same namespace, to whose statuses
+	// the rule is restricted. The rule applies to the statuses of every check when empty.
+	Check string `json:"check,omitempty"`
+	// Every is the interval at which the rule runs, as a Flux duration (e.g. 1m).
+	Every string `json:"every"`
+	// Offset delays the execution of each run, as a Flux duration (e.g. 30s).
+	Offset string `json:"offset,omitempty"`
+	// StatusRules are the level transitions for which notifications are sent.
+	//+kubebuilder:validation:MinItems=1
+	StatusRules []StatusRule `json:"statusRules"`
+	// TagRules restrict the rule to statuses with matching tags.
+	TagRules []TagRule `json:"tagRules,omitempty"`
+	// MessageTemplate is the template of each notification sent to Slack or PagerDuty endpoints.
+	MessageTemplate string `json:"messageTemplate,omitempty"`
+	// Channel is the channel to which notifications are sent by Slack endpoints using a token.
+	Channel string `json:"channel,omitempty"`
+
+	// Labels are the names of the Labels, within the same namespace and
+	// organization, which are assigned to the notification rule in each target instance.
+	// Labels assigned by other means, such as the Influx UI, are left in place.
+	Labels []string `json:"labels,omitempty"`
+
+	// DeletionPolicy determines whether the notification rule is removed
+	// from each target Influx instance when this resource is deleted.
+	//+kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// StatusRule matches the transition of a status between levels.
+type StatusRule struct {
+	// CurrentLevel is the level of the current status.
+	CurrentLevel RuleStatusLevel `json:"currentLevel"`
+	// PreviousLevel is the level of the previous status. Any transition into
+	// the current level matches when empty.
+	PreviousLevel RuleStatusLevel `json:"previousLevel,omitempty"`
+}
+
+//+kubebuilder:validation:Enum=CRIT;WARN;INFO;OK;ANY
+
+// RuleStatusLevel is a status level matched by a status rule.
+type RuleStatusLevel string
+
+// TagRule matches statuses by the value of a tag.
+type TagRule struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	// Operator determines how the value of the tag is compared with value.
+	//+kubebuilder:default=equal
+	Operator TagRuleOperator `json:"operator,omitempty"`
+}
+
+//+kubebuilder:validation:Enum=equal;notequal;equalregex;notequalregex
+
+type TagRuleOperator string
+
+const (
+	TagRuleOperatorEqual = TagRuleOperator("equal")
+)
+
+// NotificationRuleStatus defines the observed state of NotificationRule
+type NotificationRuleStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the notification rule.
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	Instances Instances `json:"instances"`
+	// LabelMappings records the identifiers of the labels assigned to the notification rule
+	// by the operator in each target instance. Only these are removed when no
+	// longer declared.
+	LabelMappings InstanceLabels `json:"labelMappings,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Organization",type=string,JSONPath=`.spec.organization`
+//+kubebuilder:printcolumn:name="Endpoint",type=string,JSONPath=`.spec.endpoint`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NotificationRule is the Schema for the notificationrules API
+type NotificationRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotificationRuleSpec   `json:"spec,omitempty"`
+	Status NotificationRuleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NotificationRuleList contains a list of NotificationRule
+type NotificationRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NotificationRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NotificationRule{}, &NotificationRuleList{})
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var notificationrulelog = logf.Log.WithName("notificationrule-resource")
+
+func (r *NotificationRule) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-paradox-macro-re-v1alpha1-notificationrule,mutating=false,failurePolicy=fail,sideEffects=None,groups=paradox.macro.re,resources=notificationrules,verbs=create;update,versions=v1alpha1,name=vnotificationrule.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &NotificationRule{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *NotificationRule) ValidateCreate() error {
+	notificationrulelog.Info("validate create", "name", r.Name)
+
+	return invalid("NotificationRule", r.Name, r.validate())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *NotificationRule) ValidateUpdate(old runtime.Object) error {
+	notificationrulelog.Info("validate update", "name", r.Name)
+
+	return invalid("NotificationRule", r.Name, validateUpdate(r, old, func(n *NotificationRule) interface{} { return n.Spec }))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *NotificationRule) ValidateDelete() error {
+	return nil
+}
+
+func (r *NotificationRule) validate() (errs field.ErrorList) {
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateRequired(path.Child("name"), r.Spec.Name)...)
+	errs = append(errs, validateRequired(path.Child("organization"), r.Spec.Organization)...)
+	errs = append(errs, validateLabels(path.Child("labels"), r.Spec.Labels)...)
+	errs = append(errs, validateRequired(path.Child("endpoint"), r.Spec.Endpoint)...)
+	errs = append(errs, validateRequired(path.Child("every"), r.Spec.Every)...)
+	errs = append(errs, validateFluxDuration(path.Child("every"), r.Spec.Every)...)
+	errs = append(errs, validateFluxDuration(path.Child("offset"), r.Spec.Offset)...)
+
+	if len(r.Spec.StatusRules) == 0 {
+		errs = append(errs, field.Required(path.Child("statusRules"), ""))
+	}
+
+	for i, rule := range r.Spec.StatusRules {
+		errs = append(errs, validateRequired(path.Child("statusRules").Index(i).Child("currentLevel"), string(rule.CurrentLevel))...)
+	}
+
+	for i, rule := range r.Spec.TagRules {
+		errs = append(errs, validateRequired(path.Child("tagRules").Index(i).Child("key"), rule.Key)...)
+	}
+
+	return errs
+}
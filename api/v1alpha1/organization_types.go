@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,9 +30,30 @@ type OrganizationSpec struct {
 	Description string `json:"description"`
 
 	// InstanceRefs is a map of namespace -> name -> authorization
-	InstanceRefs map[string]map[string]InstanceAuthorization `json:"instance_refs"`
+	InstanceRefs map[string]map[string]InstanceAuthorization `json:"instance_refs,omitempty"`
+	// InstanceSelector targets every Instance matching a label selector, in addition
+	// to those referenced by InstanceRefs. An instance which is both referenced and
+	// selected uses the authorization from InstanceRefs.
+	InstanceSelector *InstanceSelector `json:"instanceSelector,omitempty"`
+
+	// Members are the names of the Users, within the same namespace, which are
+	// members of the organization in each target instance. Members which are not
+	// managed by a User are left untouched.
+	Members []string `json:"members,omitempty"`
+	// Owners are the names of the Users, within the same namespace, which are
+	// owners of the organization in each target instance. Owners which are not
+	// managed by a User are left untouched.
+	Owners []string `json:"owners,omitempty"`
+
+	// DeletionPolicy determines whether the organization is removed from
+	// each target Influx instance when this resource is deleted. Organizations
+	// which already existed, and so were adopted, are always retained.
+	//+kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
+// InstanceAuthorization identifies the credential used to authorize requests
+// against a target instance.
 type InstanceAuthorization struct {
 	Type InstanceAuthorizationType `json:"type"`
 
@@ -39,47 +61,275 @@ type InstanceAuthorization struct {
 	Secret *SecretRef `json:"secretRef,omitempty"`
 }
 
+// InstanceSelector selects target instances by label, authorizing requests
+// against each of them with a shared credential template.
+type InstanceSelector struct {
+	// Selector selects Instances by label.
+	Selector metav1.LabelSelector `json:"selector"`
+	// NamespaceSelector selects, by label, the namespaces in which Instances are selected.
+	// Only the namespace of the organization is searched when nil, and every
+	// namespace when empty.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Authorization is the credential used for each selected instance.
+	// The namespace and name of a secretRef are Go text templates which are
+	// supplied with .Instance.Namespace and .Instance.Name of the selected instance,
+	// e.g. name: "{{ .Instance.Name }}-admin-token".
+	Authorization InstanceAuthorization `json:"authorization"`
+}
+
 type SecretRef struct {
 	Namespace string `json:"namespace"`
 	Name      string `json:"name"`
 	Key       string `json:"key"`
 }
 
-//+kubebuilder:validation:Enum=token;secret
+//+kubebuilder:validation:Enum=token;secret;instance
 
 type InstanceAuthorizationType string
 
 const (
 	InstanceAuthorizationTypeToken  = InstanceAuthorizationType("token")
 	InstanceAuthorizationTypeSecret = InstanceAuthorizationType("secret")
+	// InstanceAuthorizationTypeInstance uses the operator credential
+	// defined on the target Instance itself.
+	InstanceAuthorizationTypeInstance = InstanceAuthorizationType("instance")
+)
+
+//+kubebuilder:validation:Enum=Delete;Retain;Orphan
+
+// DeletionPolicy describes what happens to the resources created within
+// the target Influx instances when the owning Kubernetes resource is deleted.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete removes the resource from every target instance
+	// before the owning resource is released.
+	DeletionPolicyDelete = DeletionPolicy("Delete")
+	// DeletionPolicyRetain leaves the resource in every target instance.
+	// The owning resource still carries a finalizer, so any Kubernetes side
+	// clean up is performed before it is released.
+	DeletionPolicyRetain = DeletionPolicy("Retain")
+	// DeletionPolicyOrphan leaves the resource in every target instance and
+	// does not register a finalizer, so the owning resource is released
+	// without any involvement from the controller.
+	DeletionPolicyOrphan = DeletionPolicy("Orphan")
+)
+
+// DeletesInstanceResources returns true when the policy requires resources
+// to be removed from the target instances. An empty policy is treated as
+// DeletionPolicyDelete.
+func (p DeletionPolicy) DeletesInstanceResources() bool {
+	return p == "" || p == DeletionPolicyDelete
+}
+
+//+kubebuilder:validation:Enum=active;inactive
+
+// ActivityStatus determines whether a resource, such as a task or check,
+// is active within the target Influx instances.
+type ActivityStatus string
+
+const (
+	ActivityStatusActive   = ActivityStatus("active")
+	ActivityStatusInactive = ActivityStatus("inactive")
 )
 
 // OrganizationStatus defines the observed state of Organization
 type OrganizationStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the organization.
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
 	Instances Instances `json:"instances"`
+	// CreatedIDs records the identifier of the organization within each target
+	// instance in which it was created by the operator. Only these are removed
+	// upon deletion, as organizations which already existed are adopted.
+	CreatedIDs InstanceIDs `json:"createdIDs,omitempty"`
 }
 
+const (
+	// ConditionReady is true when the resource exists in every target instance
+	// and the last reconcile succeeded.
+	ConditionReady = "Ready"
+	// ConditionSynced is true when the last reconcile succeeded for every target instance.
+	ConditionSynced = "Synced"
+	// ConditionDegraded is true when the last reconcile failed for some, but not all,
+	// target instances.
+	ConditionDegraded = "Degraded"
+	// ConditionDriftCorrected is true when the last reconcile found the resource
+	// to differ from its spec in some target instance and corrected it.
+	ConditionDriftCorrected = "DriftCorrected"
+	// ConditionSchemaCompatible is false when the declared measurement schemas of a
+	// bucket require changes which a target instance cannot apply.
+	ConditionSchemaCompatible = "SchemaCompatible"
+	// ConditionTokenTargetConflict is true when a Secret or ConfigMap in which the
+	// token of an authorization is published already exists, and is not managed by
+	// the authorization, or is named by the targets of several instances, so is
+	// left untouched.
+	ConditionTokenTargetConflict = "TokenTargetConflict"
+)
+
 // Instances is a map of namespace to map of name to resource instance.
 type Instances map[string]map[string]ResourceInstance
 
+// AddInstance records id as the identifier of the resource within instance
+// and marks the resource as synced.
 func (i Instances) AddInstance(instance *Instance, id *InfluxID) {
 	if id == nil {
 		return
 	}
 
+	now := metav1.Now()
+
+	resource := i.resource(instance)
+	resource.ID = id
+	resource.LastSyncedTime = &now
+	resource.LastError = ""
+	meta.SetStatusCondition(&resource.Conditions, metav1.Condition{
+		Type:   ConditionSynced,
+		Status: metav1.ConditionTrue,
+		Reason: "Synced",
+	})
+
+	i.setResource(instance, resource)
+}
+
+// AddInstanceError records err as the reason the resource could not be synced
+// within instance. Any previously recorded identifier is retained.
+func (i Instances) AddInstanceError(instance *Instance, err error) {
+	resource := i.resource(instance)
+	resource.LastError = err.Error()
+	meta.SetStatusCondition(&resource.Conditions, metav1.Condition{
+		Type:    ConditionSynced,
+		Status:  metav1.ConditionFalse,
+		Reason:  "SyncFailed",
+		Message: err.Error(),
+	})
+
+	i.setResource(instance, resource)
+}
+
+func (i Instances) resource(instance *Instance) ResourceInstance {
+	return i[instance.ObjectMeta.Namespace][instance.ObjectMeta.Name]
+}
+
+func (i Instances) setResource(instance *Instance, resource ResourceInstance) {
 	instances, ok := i[instance.ObjectMeta.Namespace]
 	if !ok {
 		instances = map[string]ResourceInstance{}
 		i[instance.ObjectMeta.Namespace] = instances
 	}
 
-	instances[instance.ObjectMeta.Name] = ResourceInstance{ID: id}
+	instances[instance.ObjectMeta.Name] = resource
 }
 
 type ResourceInstance struct {
 	// ID is the identifier which relates to the named resource
 	// in the target InfluxData instance.
 	ID *InfluxID `json:"id,omitempty"`
+	// LastSyncedTime is the last time the resource was successfully
+	// reconciled within the target InfluxData instance.
+	LastSyncedTime *metav1.Time `json:"lastSyncedTime,omitempty"`
+	// LastError is the error encountered by the last failed attempt to
+	// reconcile the resource within the target InfluxData instance.
+	LastError string `json:"lastError,omitempty"`
+	// Conditions represent the latest available observations of the
+	// resource within the target InfluxData instance.
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// InstanceHashes is a map of namespace to map of name to the hash of the
+// content last written to the resource within that instance.
+type InstanceHashes map[string]map[string]string
+
+// Get returns the hash recorded for the instance identified by namespace and name.
+func (h InstanceHashes) Get(namespace, name string) string {
+	return h[namespace][name]
+}
+
+// Set records hash for the instance identified by namespace and name.
+func (h InstanceHashes) Set(namespace, name, hash string) {
+	namespaced, ok := h[namespace]
+	if !ok {
+		namespaced = map[string]string{}
+		h[namespace] = namespaced
+	}
+
+	namespaced[name] = hash
+}
+
+// Retain removes the hashes of every instance which is not recorded in instances.
+func (h InstanceHashes) Retain(instances Instances) {
+	retainInstances(h, instances)
+}
+
+// InstanceIDs is a map of namespace to map of name to an identifier within that instance.
+type InstanceIDs map[string]map[string]InfluxID
+
+// Get returns the identifier recorded for the instance identified by namespace and name.
+func (i InstanceIDs) Get(namespace, name string) InfluxID {
+	return i[namespace][name]
+}
+
+// Set records id for the instance identified by namespace and name.
+func (i InstanceIDs) Set(namespace, name string, id InfluxID) {
+	namespaced, ok := i[namespace]
+	if !ok {
+		namespaced = map[string]InfluxID{}
+		i[namespace] = namespaced
+	}
+
+	namespaced[name] = id
+}
+
+// Retain removes the identifiers of every instance which is not recorded in instances.
+func (i InstanceIDs) Retain(instances Instances) {
+	retainInstances(i, instances)
+}
+
+// InstanceLabels is a map of namespace to map of name to the identifiers of the
+// labels assigned to the resource by the operator within that instance.
+type InstanceLabels map[string]map[string][]InfluxID
+
+// Get returns the labels recorded for the instance identified by namespace and name.
+func (l InstanceLabels) Get(namespace, name string) []InfluxID {
+	return l[namespace][name]
+}
+
+// Set records ids as the labels for the instance identified by namespace and name.
+func (l InstanceLabels) Set(namespace, name string, ids []InfluxID) {
+	namespaced, ok := l[namespace]
+	if !ok {
+		namespaced = map[string][]InfluxID{}
+		l[namespace] = namespaced
+	}
+
+	namespaced[name] = ids
+}
+
+// Retain removes the labels of every instance which is not recorded in instances.
+func (l InstanceLabels) Retain(instances Instances) {
+	retainInstances(l, instances)
+}
+
+// retainInstances removes the entry of every instance from m which is not
+// recorded in instances, along with any namespace which is left empty.
+func retainInstances[V any](m map[string]map[string]V, instances Instances) {
+	for namespace, namespaced := range m {
+		for name := range namespaced {
+			if _, ok := instances[namespace][name]; !ok {
+				delete(namespaced, name)
+			}
+		}
+
+		if len(namespaced) == 0 {
+			delete(m, namespace)
+		}
+	}
 }
 
 // InfluxID is an int64 represented as a hexidecimally encoded string.
@@ -88,6 +338,9 @@ type InfluxID string
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:shortName=org;orgs
+//+kubebuilder:printcolumn:name="Name",type=string,JSONPath=`.spec.name`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Organization is the Schema for the organizations API
 type Organization struct {
@@ -0,0 +1,58 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInstanceHashesRetain(t *testing.T) {
+	instances := Instances{
+		"influx": {"primary": ResourceInstance{}},
+	}
+
+	tests := []struct {
+		name   string
+		hashes InstanceHashes
+		want   InstanceHashes
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:   "recorded",
+			hashes: InstanceHashes{"influx": {"primary": "abc"}},
+			want:   InstanceHashes{"influx": {"primary": "abc"}},
+		},
+		{
+			name:   "no longer recorded",
+			hashes: InstanceHashes{"influx": {"primary": "abc", "secondary": "def"}, "other": {"primary": "ghi"}},
+			want:   InstanceHashes{"influx": {"primary": "abc"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.hashes.Retain(instances)
+
+			if !reflect.DeepEqual(tt.hashes, tt.want) {
+				t.Errorf("Retain() = %v, want %v", tt.hashes, tt.want)
+			}
+		})
+	}
+}
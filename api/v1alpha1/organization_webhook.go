@@ -0,0 +1,100 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var organizationlog = logf.Log.WithName("organization-resource")
+
+func (r *Organization) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-paradox-macro-re-v1alpha1-organization,mutating=false,failurePolicy=fail,sideEffects=None,groups=paradox.macro.re,resources=organizations,verbs=create;update,versions=v1alpha1,name=vorganization.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Organization{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Organization) ValidateCreate() error {
+	organizationlog.Info("validate create", "name", r.Name)
+
+	return invalid("Organization", r.Name, r.validate())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Organization) ValidateUpdate(old runtime.Object) error {
+	organizationlog.Info("validate update", "name", r.Name)
+
+	return invalid("Organization", r.Name, validateUpdate(r, old, func(o *Organization) interface{} { return o.Spec }))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Organization) ValidateDelete() error {
+	return nil
+}
+
+func (r *Organization) validate() (errs field.ErrorList) {
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateRequired(path.Child("name"), r.Spec.Name)...)
+
+	for namespace, refs := range r.Spec.InstanceRefs {
+		for name, auth := range refs {
+			errs = append(errs, validateInstanceAuthorization(path.Child("instance_refs").Key(namespace).Key(name), auth, true)...)
+		}
+	}
+
+	if selector := r.Spec.InstanceSelector; selector != nil {
+		selectorPath := path.Child("instanceSelector")
+		errs = append(errs, validateLabelSelector(selectorPath.Child("selector"), &selector.Selector)...)
+		errs = append(errs, validateLabelSelector(selectorPath.Child("namespaceSelector"), selector.NamespaceSelector)...)
+
+		authPath := selectorPath.Child("authorization")
+		errs = append(errs, validateInstanceAuthorization(authPath, selector.Authorization, true)...)
+
+		if ref := selector.Authorization.Secret; ref != nil {
+			errs = append(errs, validateTemplate(authPath.Child("secretRef", "namespace"), ref.Namespace)...)
+			errs = append(errs, validateTemplate(authPath.Child("secretRef", "name"), ref.Name)...)
+		}
+	}
+
+	errs = append(errs, validateNames(path.Child("members"), r.Spec.Members)...)
+	errs = append(errs, validateNames(path.Child("owners"), r.Spec.Owners)...)
+
+	// a user is either a member or an owner of the organization, never both
+	members := make(map[string]struct{}, len(r.Spec.Members))
+	for _, member := range r.Spec.Members {
+		members[member] = struct{}{}
+	}
+
+	for i, owner := range r.Spec.Owners {
+		if _, ok := members[owner]; ok {
+			errs = append(errs, field.Invalid(path.Child("owners").Index(i), owner, "user is also declared as a member"))
+		}
+	}
+
+	return errs
+}
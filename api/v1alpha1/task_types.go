@@ -0,0 +1,137 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TaskSpec defines the desired state of Task
+type TaskSpec struct {
+	// Name is the name of the task in the target Influx instance.
+	Name string `json:"name"`
+	// Organization is the parent organization which owns this task
+	// within the target InfluxData instance.
+	Organization string `json:"organization"`
+	// Description is a string which describes any useful details
+	// regarding the purpose of the task.
+	Description string `json:"description,omitempty"`
+	// Script is the Flux script run by the task. The task option is generated
+	// from the name and schedule of the task, and must not be declared.
+	// Exactly one of script and scriptConfigMapRef must be set.
+	Script string `json:"script,omitempty"`
+	// ScriptConfigMapRef identifies a key of a ConfigMap holding the Flux script
+	// run by the task, for scripts which are managed alongside other configuration.
+	ScriptConfigMapRef *ConfigMapRef `json:"scriptConfigMapRef,omitempty"`
+	// Every is the interval at which the task runs, as a Flux duration (e.g. 1h).
+	// Exactly one of every and cron must be set.
+	Every string `json:"every,omitempty"`
+	// Cron is the cron expression on which the task runs (e.g. "0 * * * *").
+	Cron string `json:"cron,omitempty"`
+	// Offset delays the execution of each run, as a Flux duration (e.g. 5m),
+	// to allow late data to arrive.
+	Offset string `json:"offset,omitempty"`
+	// Status determines whether the task is scheduled to run.
+	//+kubebuilder:default=active
+	Status ActivityStatus `json:"status,omitempty"`
+
+	// Labels are the names of the Labels, within the same namespace and
+	// organization, which are assigned to the task in each target instance.
+	// Labels assigned by other means, such as the Influx UI, are left in place.
+	Labels []string `json:"labels,omitempty"`
+
+	// DeletionPolicy determines whether the task is removed from
+	// each target Influx instance when this resource is deleted.
+	//+kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// TaskStatus defines the observed state of Task
+type TaskStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the task.
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	Instances Instances `json:"instances"`
+	// Runs records the most recent run of the task in each target instance.
+	Runs TaskRuns `json:"runs,omitempty"`
+	// LabelMappings records the identifiers of the labels assigned to the task
+	// by the operator in each target instance. Only these are removed when no
+	// longer declared.
+	LabelMappings InstanceLabels `json:"labelMappings,omitempty"`
+}
+
+// TaskRuns is a map of namespace to map of name to task run.
+type TaskRuns map[string]map[string]TaskRun
+
+// Set records run as the most recent run for the instance identified by namespace and name.
+func (t TaskRuns) Set(namespace, name string, run TaskRun) {
+	namespaced, ok := t[namespace]
+	if !ok {
+		namespaced = map[string]TaskRun{}
+		t[namespace] = namespaced
+	}
+
+	namespaced[name] = run
+}
+
+// Retain removes the runs of every instance which is not recorded in instances.
+func (t TaskRuns) Retain(instances Instances) {
+	retainInstances(t, instances)
+}
+
+// TaskRun is the state of the most recent run of a task within a single target instance.
+type TaskRun struct {
+	// LastRunStatus is the outcome of the most recent run (success, failed or canceled).
+	LastRunStatus string `json:"lastRunStatus,omitempty"`
+	// LastRunError is the error of the most recent run, when it failed.
+	LastRunError string `json:"lastRunError,omitempty"`
+	// LatestCompleted is the time up to which the task has completed runs.
+	LatestCompleted *metav1.Time `json:"latestCompleted,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Organization",type=string,JSONPath=`.spec.organization`
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.spec.status`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Task is the Schema for the tasks API
+type Task struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TaskSpec   `json:"spec,omitempty"`
+	Status TaskStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TaskList contains a list of Task
+type TaskList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Task `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Task{}, &TaskList{})
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var tasklog = logf.Log.WithName("task-resource")
+
+// taskOption matches a task option declared within a Flux script.
+var taskOption = regexp.MustCompile(`(?m)^\s*option\s+task\s*=`)
+
+// DeclaresTaskOption reports whether script declares its own task option, which
+// conflicts with the option generated from the spec of a Task.
+func DeclaresTaskOption(script string) bool {
+	return taskOption.MatchString(script)
+}
+
+func (r *Task) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-paradox-macro-re-v1alpha1-task,mutating=false,failurePolicy=fail,sideEffects=None,groups=paradox.macro.re,resources=tasks,verbs=create;update,versions=v1alpha1,name=vtask.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Task{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Task) ValidateCreate() error {
+	tasklog.Info("validate create", "name", r.Name)
+
+	return invalid("Task", r.Name, r.validate())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Task) ValidateUpdate(old runtime.Object) error {
+	tasklog.Info("validate update", "name", r.Name)
+
+	return invalid("Task", r.Name, validateUpdate(r, old, func(t *Task) interface{} { return t.Spec }))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Task) ValidateDelete() error {
+	return nil
+}
+
+func (r *Task) validate() (errs field.ErrorList) {
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateRequired(path.Child("name"), r.Spec.Name)...)
+	errs = append(errs, validateRequired(path.Child("organization"), r.Spec.Organization)...)
+	errs = append(errs, validateLabels(path.Child("labels"), r.Spec.Labels)...)
+
+	switch ref := r.Spec.ScriptConfigMapRef; {
+	case ref == nil && strings.TrimSpace(r.Spec.Script) == "":
+		errs = append(errs, field.Required(path.Child("script"), "one of script or scriptConfigMapRef is required"))
+	case ref != nil && r.Spec.Script != "":
+		errs = append(errs, field.Forbidden(path.Child("scriptConfigMapRef"), "not permitted when script is set"))
+	case ref != nil:
+		refPath := path.Child("scriptConfigMapRef")
+		errs = append(errs, validateRequired(refPath.Child("namespace"), ref.Namespace)...)
+		errs = append(errs, validateRequired(refPath.Child("name"), ref.Name)...)
+		errs = append(errs, validateRequired(refPath.Child("key"), ref.Key)...)
+	}
+
+	if DeclaresTaskOption(r.Spec.Script) {
+		errs = append(errs, field.Invalid(path.Child("script"), "option task", "must not declare the task option, which is generated from the spec"))
+	}
+
+	switch {
+	case r.Spec.Every == "" && r.Spec.Cron == "":
+		errs = append(errs, field.Required(path.Child("every"), "one of every or cron is required"))
+	case r.Spec.Every != "" && r.Spec.Cron != "":
+		errs = append(errs, field.Forbidden(path.Child("cron"), "not permitted when every is set"))
+	}
+
+	errs = append(errs, validateFluxDuration(path.Child("every"), r.Spec.Every)...)
+	errs = append(errs, validateFluxDuration(path.Child("offset"), r.Spec.Offset)...)
+
+	if strings.ContainsAny(r.Spec.Cron, "\"\n") {
+		errs = append(errs, field.Invalid(path.Child("cron"), r.Spec.Cron, "must be a single cron expression"))
+	}
+
+	return errs
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UserSpec defines the desired state of User
+type UserSpec struct {
+	// Name is the name of the user in the target Influx instances.
+	Name string `json:"name"`
+	// Organization is the organization whose target instances the user is
+	// created in. Users are not owned by an organization within Influx, so
+	// membership of any organization is declared by its members and owners.
+	Organization string `json:"organization"`
+	// PasswordSecretRef identifies the Secret key holding the password of the user.
+	// The user is created without a password when unset.
+	PasswordSecretRef *SecretRef `json:"passwordSecretRef,omitempty"`
+	// Status determines whether the user is permitted to sign in.
+	//+kubebuilder:default=active
+	Status ActivityStatus `json:"status,omitempty"`
+	// Adopt permits an existing user of the same name, which was not created
+	// by this resource, to be managed by it, including its password and its
+	// removal upon deletion. Such users are otherwise reported as an error.
+	Adopt bool `json:"adopt,omitempty"`
+
+	// DeletionPolicy determines whether the user is removed from
+	// each target Influx instance when this resource is deleted.
+	//+kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// UserStatus defines the observed state of User
+type UserStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the user.
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	Instances Instances `json:"instances"`
+	// PasswordHashes identifies the password last written to the user in each
+	// target instance. Influx does not return passwords, so the password is
+	// rewritten whenever it changes.
+	PasswordHashes InstanceHashes `json:"passwordHashes,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Name",type=string,JSONPath=`.spec.name`
+//+kubebuilder:printcolumn:name="Organization",type=string,JSONPath=`.spec.organization`
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.spec.status`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// User is the Schema for the users API
+type User struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UserSpec   `json:"spec,omitempty"`
+	Status UserStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// UserList contains a list of User
+type UserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []User `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&User{}, &UserList{})
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var userlog = logf.Log.WithName("user-resource")
+
+// userInstanceReader reads the instances whose onboarding users may not be
+// declared as users.
+var userInstanceReader client.Reader
+
+func (r *User) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	userInstanceReader = mgr.GetClient()
+
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-paradox-macro-re-v1alpha1-user,mutating=false,failurePolicy=fail,sideEffects=None,groups=paradox.macro.re,resources=users,verbs=create;update,versions=v1alpha1,name=vuser.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &User{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *User) ValidateCreate() error {
+	userlog.Info("validate create", "name", r.Name)
+
+	return invalid("User", r.Name, r.validate())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *User) ValidateUpdate(old runtime.Object) error {
+	userlog.Info("validate update", "name", r.Name)
+
+	return invalid("User", r.Name, validateUpdate(r, old, func(u *User) interface{} { return u.Spec }))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *User) ValidateDelete() error {
+	return nil
+}
+
+func (r *User) validate() (errs field.ErrorList) {
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateRequired(path.Child("name"), r.Spec.Name)...)
+	errs = append(errs, validateRequired(path.Child("organization"), r.Spec.Organization)...)
+	errs = append(errs, validateNotOnboardingUser(path.Child("name"), r.Spec.Name)...)
+
+	if ref := r.Spec.PasswordSecretRef; ref != nil {
+		errs = append(errs, validateSecretRef(path.Child("passwordSecretRef"), *ref)...)
+	}
+
+	return errs
+}
+
+// validateNotOnboardingUser rejects the name of the onboarding user of any
+// instance, as its password holds the operator credentials of the instance.
+func validateNotOnboardingUser(path *field.Path, name string) field.ErrorList {
+	if userInstanceReader == nil || name == "" {
+		return nil
+	}
+
+	var instances InstanceList
+	if err := userInstanceReader.List(context.Background(), &instances); err != nil {
+		return field.ErrorList{field.InternalError(path, err)}
+	}
+
+	for _, instance := range instances.Items {
+		if onboarding := instance.Spec.Onboarding; onboarding != nil && onboarding.Username == name {
+			return field.ErrorList{field.Forbidden(path, "is the onboarding user of instance "+instance.Namespace+"/"+instance.Name)}
+		}
+	}
+
+	return nil
+}
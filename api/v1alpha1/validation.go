@@ -0,0 +1,250 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// invalid returns an Invalid error for the named resource of kind when errs
+// is not empty, and nil otherwise.
+func invalid(kind, name string, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: kind}, name, errs)
+}
+
+// validatedObject is a resource whose spec is validated by its webhook.
+type validatedObject interface {
+	metav1.Object
+	validate() field.ErrorList
+}
+
+// validateUpdate validates the update of obj from old, where spec returns the spec
+// of either. Nothing is validated for objects which are being deleted, or whose spec
+// is unchanged, such as when a finalizer is removed. Otherwise only errors which
+// old did not already have are returned, so that objects admitted before a
+// validation was introduced can still be updated without first correcting every
+// field which is unchanged.
+func validateUpdate[T validatedObject](obj T, old runtime.Object, spec func(T) interface{}) field.ErrorList {
+	previous, ok := old.(T)
+	if !ok {
+		return obj.validate()
+	}
+
+	if obj.GetDeletionTimestamp() != nil || equality.Semantic.DeepEqual(spec(obj), spec(previous)) {
+		return nil
+	}
+
+	existing := map[string]struct{}{}
+	for _, err := range previous.validate() {
+		existing[err.Error()] = struct{}{}
+	}
+
+	var errs field.ErrorList
+	for _, err := range obj.validate() {
+		if _, ok := existing[err.Error()]; !ok {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// validateRequired checks that value is not empty.
+func validateRequired(path *field.Path, value string) field.ErrorList {
+	if value == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	return nil
+}
+
+// validateDuration checks that value, when not empty, is a non-negative duration.
+func validateDuration(path *field.Path, value string) field.ErrorList {
+	if value == "" {
+		return nil
+	}
+
+	dur, err := time.ParseDuration(value)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, value, err.Error())}
+	}
+
+	if dur < 0 {
+		return field.ErrorList{field.Invalid(path, value, "must not be negative")}
+	}
+
+	return nil
+}
+
+// fluxDuration matches a Flux duration literal, such as 1h30m or 1mo.
+var fluxDuration = regexp.MustCompile(`^([0-9]+(y|mo|w|d|h|ms|us|µs|ns|m|s))+$`)
+
+// validateFluxDuration checks that value, when not empty, is a Flux duration literal.
+func validateFluxDuration(path *field.Path, value string) field.ErrorList {
+	if value == "" || fluxDuration.MatchString(value) {
+		return nil
+	}
+
+	return field.ErrorList{field.Invalid(path, value, "must be a Flux duration (e.g. 1h30m)")}
+}
+
+// validateDecimal checks that value is a decimal number.
+func validateDecimal(path *field.Path, value string) field.ErrorList {
+	if value == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return field.ErrorList{field.Invalid(path, value, "must be a decimal number")}
+	}
+
+	return nil
+}
+
+// validateExactlyOne checks that exactly one of the named fields of path is set,
+// where set reports whether each field of names is set.
+func validateExactlyOne(path *field.Path, names []string, set []bool) field.ErrorList {
+	var declared []string
+	for i, name := range names {
+		if set[i] {
+			declared = append(declared, name)
+		}
+	}
+
+	switch len(declared) {
+	case 0:
+		return field.ErrorList{field.Required(path.Child(names[0]), "one of "+strings.Join(names, ", ")+" is required")}
+	case 1:
+		return nil
+	default:
+		return field.ErrorList{field.Forbidden(path.Child(declared[1]), "not permitted when "+declared[0]+" is set")}
+	}
+}
+
+// validateLabels checks that each of labels names a Label, at most once.
+func validateLabels(path *field.Path, labels []string) field.ErrorList {
+	return validateNames(path, labels)
+}
+
+// validateNames checks that each of names is set, and appears at most once.
+func validateNames(path *field.Path, names []string) (errs field.ErrorList) {
+	seen := make(map[string]struct{}, len(names))
+	for i, name := range names {
+		errs = append(errs, validateRequired(path.Index(i), name)...)
+
+		if _, ok := seen[name]; ok {
+			errs = append(errs, field.Duplicate(path.Index(i), name))
+		}
+
+		seen[name] = struct{}{}
+	}
+
+	return errs
+}
+
+// validateTemplate checks that value parses as a text/template.
+func validateTemplate(path *field.Path, value string) field.ErrorList {
+	if _, err := template.New("").Option("missingkey=error").Parse(value); err != nil {
+		return field.ErrorList{field.Invalid(path, value, err.Error())}
+	}
+
+	return nil
+}
+
+// validateSecretRef checks that ref identifies a single key of a Secret.
+func validateSecretRef(path *field.Path, ref SecretRef) (errs field.ErrorList) {
+	errs = append(errs, validateRequired(path.Child("namespace"), ref.Namespace)...)
+	errs = append(errs, validateRequired(path.Child("name"), ref.Name)...)
+	errs = append(errs, validateRequired(path.Child("key"), ref.Key)...)
+
+	return errs
+}
+
+// validateInstanceAuthorization checks that auth defines the credential its type
+// requires, and no other. Instance authorizations are only permitted when allowInstance
+// is true, as an Instance cannot defer to its own credential.
+func validateInstanceAuthorization(path *field.Path, auth InstanceAuthorization, allowInstance bool) (errs field.ErrorList) {
+	switch auth.Type {
+	case InstanceAuthorizationTypeToken:
+		if auth.Token == nil || *auth.Token == "" {
+			errs = append(errs, field.Required(path.Child("token"), "required when type is token"))
+		}
+
+		if auth.Secret != nil {
+			errs = append(errs, field.Forbidden(path.Child("secretRef"), "not permitted when type is token"))
+		}
+	case InstanceAuthorizationTypeSecret:
+		if auth.Secret == nil {
+			errs = append(errs, field.Required(path.Child("secretRef"), "required when type is secret"))
+		} else {
+			errs = append(errs, validateSecretRef(path.Child("secretRef"), *auth.Secret)...)
+		}
+
+		if auth.Token != nil {
+			errs = append(errs, field.Forbidden(path.Child("token"), "not permitted when type is secret"))
+		}
+	case InstanceAuthorizationTypeInstance:
+		if !allowInstance {
+			errs = append(errs, field.NotSupported(path.Child("type"), auth.Type,
+				[]string{string(InstanceAuthorizationTypeToken), string(InstanceAuthorizationTypeSecret)}))
+		}
+
+		if auth.Token != nil {
+			errs = append(errs, field.Forbidden(path.Child("token"), "not permitted when type is instance"))
+		}
+
+		if auth.Secret != nil {
+			errs = append(errs, field.Forbidden(path.Child("secretRef"), "not permitted when type is instance"))
+		}
+	default:
+		errs = append(errs, field.NotSupported(path.Child("type"), auth.Type, []string{
+			string(InstanceAuthorizationTypeToken),
+			string(InstanceAuthorizationTypeSecret),
+			string(InstanceAuthorizationTypeInstance),
+		}))
+	}
+
+	return errs
+}
+
+// validateLabelSelector checks that selector, when set, can be converted to a selector.
+func validateLabelSelector(path *field.Path, selector *metav1.LabelSelector) field.ErrorList {
+	if selector == nil {
+		return nil
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		return field.ErrorList{field.Invalid(path, selector, err.Error())}
+	}
+
+	return nil
+}
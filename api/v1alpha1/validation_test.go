@@ -0,0 +1,242 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// errorFields summarises errs as the type and path of each error.
+func errorFields(errs field.ErrorList) []string {
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, string(err.Type)+" "+err.Field)
+	}
+
+	return fields
+}
+
+func TestValidateDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: ""},
+		{value: "1h30m"},
+		{value: "0s"},
+		{value: "-1h", want: []string{"FieldValueInvalid spec.duration"}},
+		{value: "1 day", want: []string{"FieldValueInvalid spec.duration"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got := errorFields(validateDuration(field.NewPath("spec", "duration"), tt.value))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "plain"},
+		{value: "{{ .Instance.Name }}-token"},
+		{value: "{{ .Instance.Name", want: []string{"FieldValueInvalid spec.nameTemplate"}},
+		{value: "{{ if .Token }}", want: []string{"FieldValueInvalid spec.nameTemplate"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got := errorFields(validateTemplate(field.NewPath("spec", "nameTemplate"), tt.value))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateTemplate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateInstanceAuthorization(t *testing.T) {
+	token := "secret"
+	ref := &SecretRef{Namespace: "influx", Name: "credentials", Key: "token"}
+
+	tests := []struct {
+		name          string
+		auth          InstanceAuthorization
+		allowInstance bool
+		want          []string
+	}{
+		{
+			name: "token",
+			auth: InstanceAuthorization{Type: InstanceAuthorizationTypeToken, Token: &token},
+		},
+		{
+			name: "token missing",
+			auth: InstanceAuthorization{Type: InstanceAuthorizationTypeToken},
+			want: []string{"FieldValueRequired auth.token"},
+		},
+		{
+			name: "token alongside secret",
+			auth: InstanceAuthorization{Type: InstanceAuthorizationTypeToken, Token: &token, Secret: ref},
+			want: []string{"FieldValueForbidden auth.secretRef"},
+		},
+		{
+			name: "secret",
+			auth: InstanceAuthorization{Type: InstanceAuthorizationTypeSecret, Secret: ref},
+		},
+		{
+			name: "secret reference incomplete",
+			auth: InstanceAuthorization{Type: InstanceAuthorizationTypeSecret, Secret: &SecretRef{Name: "credentials"}},
+			want: []string{"FieldValueRequired auth.secretRef.namespace", "FieldValueRequired auth.secretRef.key"},
+		},
+		{
+			name: "secret missing",
+			auth: InstanceAuthorization{Type: InstanceAuthorizationTypeSecret, Token: &token},
+			want: []string{"FieldValueRequired auth.secretRef", "FieldValueForbidden auth.token"},
+		},
+		{
+			name:          "instance",
+			auth:          InstanceAuthorization{Type: InstanceAuthorizationTypeInstance},
+			allowInstance: true,
+		},
+		{
+			name: "instance not permitted",
+			auth: InstanceAuthorization{Type: InstanceAuthorizationTypeInstance},
+			want: []string{"FieldValueNotSupported auth.type"},
+		},
+		{
+			name:          "instance alongside token",
+			auth:          InstanceAuthorization{Type: InstanceAuthorizationTypeInstance, Token: &token},
+			allowInstance: true,
+			want:          []string{"FieldValueForbidden auth.token"},
+		},
+		{
+			name:          "unknown type",
+			auth:          InstanceAuthorization{Type: "password"},
+			allowInstance: true,
+			want:          []string{"FieldValueNotSupported auth.type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errorFields(validateInstanceAuthorization(field.NewPath("auth"), tt.auth, tt.allowInstance))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateInstanceAuthorization() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLabelSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector *metav1.LabelSelector
+		want     []string
+	}{
+		{name: "unset"},
+		{name: "match labels", selector: &metav1.LabelSelector{MatchLabels: map[string]string{"metrics": "true"}}},
+		{
+			name: "invalid operator",
+			selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "metrics", Operator: "Matches", Values: []string{"true"}},
+			}},
+			want: []string{"FieldValueInvalid spec.namespaceSelector"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errorFields(validateLabelSelector(field.NewPath("spec", "namespaceSelector"), tt.selector))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateLabelSelector() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	bucket := func(name, retention string) *Bucket {
+		return &Bucket{Spec: BucketSpec{Name: name, Organization: "macro", RetentionPolicy: retention}}
+	}
+
+	deleting := bucket("", "30d")
+	deleting.DeletionTimestamp = &metav1.Time{}
+
+	tests := []struct {
+		name     string
+		previous runtime.Object
+		bucket   *Bucket
+		want     []string
+	}{
+		{
+			name:     "valid change",
+			previous: bucket("metrics", "720h"),
+			bucket:   bucket("metrics", "1440h"),
+		},
+		{
+			name:     "invalid change",
+			previous: bucket("metrics", "720h"),
+			bucket:   bucket("metrics", "30d"),
+			want:     []string{"FieldValueInvalid spec.retention_policy"},
+		},
+		{
+			name:     "unchanged invalid spec",
+			previous: bucket("", "30d"),
+			bucket:   bucket("", "30d"),
+		},
+		{
+			name:     "being deleted",
+			previous: bucket("", "30d"),
+			bucket:   deleting,
+		},
+		{
+			name:     "invalid value changed",
+			previous: bucket("", "30d"),
+			bucket:   bucket("", "60d"),
+			want:     []string{"FieldValueInvalid spec.retention_policy"},
+		},
+		{
+			name:     "pre-existing errors pass through",
+			previous: bucket("", "30d"),
+			bucket:   func() *Bucket { b := bucket("", "30d"); b.Spec.Description = "metrics"; return b }(),
+		},
+		{
+			name:     "previous object of another kind",
+			previous: &Organization{},
+			bucket:   bucket("", "720h"),
+			want:     []string{"FieldValueRequired spec.name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errorFields(validateUpdate(tt.bucket, tt.previous, func(b *Bucket) interface{} { return b.Spec }))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateUpdate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
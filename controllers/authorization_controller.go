@@ -17,10 +17,13 @@ limitations under the License.
 package controllers
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"html/template"
+	nethttp "net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	influxdb "github.com/influxdata/influxdb-client-go/v2"
 	"github.com/influxdata/influxdb-client-go/v2/domain"
@@ -28,9 +31,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
 )
@@ -38,19 +47,30 @@ import (
 // AuthorizationReconciler reconciles a Authorization object
 type AuthorizationReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Clients is the pool of Influx clients shared by every reconciler.
+	Clients *ClientPool
+
+	// ResyncInterval is the interval at which each authorization is reconciled
+	// against its target instances, unless overridden by annotation.
+	ResyncInterval time.Duration
 }
 
 //+kubebuilder:rbac:groups=paradox.macro.re,resources=authorizations,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=paradox.macro.re,resources=authorizations/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=paradox.macro.re,resources=authorizations/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the Authorization object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
+// As Influx permissions are immutable, the token of each instance is rotated by
+// creating a new authorization when its permissions change, when it reaches the
+// rotateAfter age or when rotation is requested by annotation. Rotated tokens
+// remain valid for the grace period once their replacement has been stored.
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
@@ -66,6 +86,28 @@ func (r *AuthorizationReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	log = log.WithValues("authorization", authorization)
 
+	if !authorization.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := finalize(ctx, r.Client, &authorization, authorization.Spec.DeletionPolicy, func() error {
+			if err := r.deleteInstanceAuthorizations(ctx, &authorization); err != nil {
+				return err
+			}
+
+			return r.deleteTokenTargets(ctx, &authorization, nil)
+		}); err != nil {
+			log.Error(err, "failed to finalize authorization")
+
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if err := syncFinalizer(ctx, r.Client, &authorization, authorization.Spec.DeletionPolicy); err != nil {
+		log.Error(err, "failed to update finalizers")
+
+		return ctrl.Result{}, err
+	}
+
 	var organization paradoxv1alpha1.Organization
 	if err := r.Get(ctx, types.NamespacedName{
 		Namespace: req.NamespacedName.Namespace,
@@ -73,145 +115,580 @@ func (r *AuthorizationReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}, &organization); err != nil {
 		log.Error(err, "unable to fetch organization")
 
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		return ctrl.Result{}, client.IgnoreNotFound(r.updateStatus(ctx, &authorization, authorization.Status.Instances, fmt.Errorf("organization %q: %w", authorization.Spec.Organization, err)))
 	}
 
-	status := paradoxv1alpha1.AuthorizationStatus{
-		Instances: paradoxv1alpha1.Instances{},
+	rotation, err := newTokenRotation(&authorization)
+	if err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, &authorization, authorization.Status.Instances, err)
 	}
 
-	if err := forEachInstanceClient(ctx, r.Client, &organization, func(instance *paradoxv1alpha1.Instance, iclient influxdb.Client) error {
+	targeted, err := instanceTargets(ctx, r.Client, &organization)
+	if err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, &authorization, authorization.Status.Instances, err)
+	}
+
+	var (
+		mu      sync.Mutex
+		tokens  = authorization.Status.Tokens.DeepCopy()
+		targets = map[tokenTargetKey]struct{}{}
+		claims  = map[tokenTargetKey]types.NamespacedName{}
+		next    time.Time
+	)
+
+	if tokens == nil {
+		tokens = paradoxv1alpha1.TokenInstances{}
+	}
+
+	instances, err := reconcileInstances(ctx, r.Client, r.Clients, &organization, authorization.Status.Instances, func(instance *paradoxv1alpha1.Instance, iclient influxdb.Client) (*paradoxv1alpha1.InfluxID, error) {
 		namespace, name := instance.ObjectMeta.Namespace, instance.ObjectMeta.Name
 		orgInstance, ok := organization.Status.Instances[namespace][name]
 		if !ok || orgInstance.ID == nil {
-			return fmt.Errorf("organization does not have an ID")
-		}
-
-		var (
-			authAPI = iclient.AuthorizationsAPI()
-			auth    *domain.Authorization
-		)
-
-		authInstance, ok := authorization.Status.Instances[namespace][name]
-		if !ok || authInstance.ID == nil {
-			permissions := []domain.Permission{}
-			auth = &domain.Authorization{
-				AuthorizationUpdateRequest: domain.AuthorizationUpdateRequest{
-					Description: &authorization.Spec.Description,
-				},
-				OrgID:       toStringPtr(orgInstance.ID),
-				Permissions: &permissions,
-			}
+			return nil, fmt.Errorf("organization does not have an ID")
+		}
 
-			for _, permission := range authorization.Spec.Permissions {
-				perm := domain.Permission{
-					Action: domain.PermissionAction(permission.Action),
-					Resource: domain.Resource{
-						Type:  domain.ResourceType(permission.Resource.ResourceType),
-						OrgID: toStringPtr(orgInstance.ID),
-					},
-				}
+		data, err := r.tokenTemplateData(ctx, &authorization, &organization, instance, orgInstance.ID)
+		if err != nil {
+			return nil, err
+		}
 
-				switch perm.Resource.Type {
-				case "buckets":
-					var bucket paradoxv1alpha1.Bucket
-					if err := r.Get(ctx, types.NamespacedName{
-						Namespace: req.NamespacedName.Namespace,
-						Name:      permission.Resource.Name,
-					}, &bucket); err != nil {
-						return err
-					}
-
-					bucketInstance := bucket.Status.Instances[namespace][name]
-
-					perm.Resource.Id = toStringPtr(bucketInstance.ID)
-				default:
-					return fmt.Errorf("unsupported resource type %q", perm.Resource.Type)
-				}
+		delivery, err := r.resolveTokenDelivery(ctx, &authorization, data)
+		if err != nil {
+			return nil, err
+		}
 
-				*auth.Permissions = append(*auth.Permissions, perm)
+		delivery.targeted = targeted
+
+		mu.Lock()
+		token := tokens.Get(namespace, name)
+		err = claimTokenTargets(claims, delivery)
+		mu.Unlock()
+
+		if err != nil {
+			// the target names of instances must differ, such as by naming the
+			// instance, so that the token of each is published
+			return nil, err
+		}
+
+		id, err := r.reconcileToken(ctx, &authorization, rotation, delivery, orgInstance.ID, iclient, &token)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		tokens.Set(namespace, name, token)
+
+		for _, target := range delivery.targets {
+			targets[tokenTargetKey{target.kind, target.key}] = struct{}{}
+		}
+
+		if deadline := rotation.deadline(token); !deadline.IsZero() && (next.IsZero() || deadline.Before(next)) {
+			next = deadline
+		}
+
+		return id, err
+	})
+	if err == nil {
+		// remove token targets of instances which are no longer targeted,
+		// or which were named differently by a previous token spec
+		err = r.deleteTokenTargets(ctx, &authorization, targets)
+	}
+
+	if err != nil {
+		log.Error(err, "error while configuring instances")
+	} else {
+		// drop tokens of instances which are no longer targeted
+		for namespace, namespaced := range tokens {
+			for name := range namespaced {
+				if _, ok := instances[namespace][name]; !ok {
+					delete(namespaced, name)
+				}
 			}
 
-			var err error
-			auth, err = authAPI.CreateAuthorization(ctx, auth)
-			if err != nil {
-				return err
+			if len(namespaced) == 0 {
+				delete(tokens, namespace)
 			}
+		}
+	}
 
-			log.V(1).Info("Authorization created", "resource", *auth.Id)
+	authorization.Status.Tokens = tokens
+	setTokenTargetConflictCondition(&authorization.Status.Conditions, authorization.Generation, tokenTargetConflicts(err))
 
-			status.Instances.AddInstance(
-				instance,
-				fromStringPtr[paradoxv1alpha1.InfluxID](auth.Id),
-			)
+	if err := r.updateStatus(ctx, &authorization, instances, err); err != nil {
+		return ctrl.Result{}, err
+	}
 
-			if spec := authorization.Spec.Token.SecretSpec; spec != nil {
-				nameTmpl, err := template.New("").Parse(spec.NameTemplate)
-				if err != nil {
-					return fmt.Errorf("attempting secret creation: %w", err)
-				}
+	log.V(4).Info("status updated")
 
-				var buf bytes.Buffer
-				if err := nameTmpl.Execute(&buf, struct {
-					Instance struct {
-						Namespace string
-						Name      string
-					}
-				}{
-					Instance: struct {
-						Namespace string
-						Name      string
-					}{namespace, name},
-				}); err != nil {
-					return fmt.Errorf("attempting secret creation: %w", err)
-				}
+	result := resyncResult(ctx, &authorization, r.ResyncInterval)
+	if !next.IsZero() {
+		// requeue in time to rotate, or delete rotated, tokens
+		until := time.Until(next)
+		if until < time.Second {
+			until = time.Second
+		}
 
-				secret := &corev1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      buf.String(),
-						Namespace: spec.Namespace,
-					},
-					StringData: map[string]string{
-						spec.Key: *auth.Token,
-					},
-				}
+		if result.RequeueAfter == 0 || until < result.RequeueAfter {
+			result.RequeueAfter = until
+		}
+	}
+
+	return result, nil
+}
+
+// reconcileToken ensures a valid token exists for authorization within the instance
+// described by delivery, creating it when absent and rotating it when required. Rotated tokens are deleted
+// once their grace period ends. The state of the token is recorded in token and the
+// identifier of the current authorization is returned.
+func (r *AuthorizationReconciler) reconcileToken(
+	ctx context.Context,
+	authorization *paradoxv1alpha1.Authorization,
+	rotation tokenRotation,
+	delivery tokenDelivery,
+	orgID *paradoxv1alpha1.InfluxID,
+	iclient influxdb.Client,
+	token *paradoxv1alpha1.TokenInstance,
+) (*paradoxv1alpha1.InfluxID, error) {
+	namespace, name := delivery.data.Instance.Namespace, delivery.data.Instance.Name
+	log := log.FromContext(ctx).WithValues("instance", namespace+"/"+name)
+
+	current := authorization.Status.Instances[namespace][name].ID
+
+	permissions, err := r.domainPermissions(ctx, authorization, orgID, namespace, name)
+	if err != nil {
+		return current, err
+	}
+
+	hash := permissionsHash(permissions)
+	now := metav1.NewTime(rotation.now)
+
+	// the state of the token is missing from the status, as it is for tokens
+	// created before it was recorded
+	untracked := token.CreatedTime == nil
+
+	if current != nil {
+		exists, err := checkAuthorization(ctx, iclient, *current)
+		switch {
+		case err != nil:
+			return current, err
+		case !exists:
+			log.Info("authorization no longer exists, recreating", "resource", *current)
+
+			current = nil
+		case token.PermissionsHash == "":
+			// tokens created before their state was recorded are adopted as they are
+			token.CreatedTime = &now
+			token.PermissionsHash = hash
+			token.RotateRequest = rotation.request
+		}
+	}
+
+	// listing every authorization of the organization is costly, so unrecorded
+	// authorizations are only searched for when the token is not known to be in place
+	if current == nil || untracked {
+		current, err = r.recoverTokens(ctx, authorization, rotation, delivery, iclient, orgID, hash, current, token)
+		if err != nil {
+			return current, err
+		}
+	}
+
+	reason := "created"
+	if current != nil {
+		reason = rotation.reason(*token, hash)
+	}
+
+	if reason == "" {
+		// the token cannot be read back from the instance, so a lost
+		// token secret can only be restored by issuing a new token
+		existing, exists, err := r.readToken(ctx, delivery, *current)
+		if err != nil {
+			return current, err
+		}
+
+		if !exists {
+			reason = "token secret missing"
+		} else if _, err := r.writeTokenTargets(ctx, authorization, delivery, *current, existing); err != nil {
+			// keep every target, along with its templated contents, up to date
+			return current, err
+		}
+	}
+
+	var errs []error
+
+	if reason != "" {
+		id, err := r.issueToken(ctx, authorization, delivery, iclient, orgID, permissions)
+		if id == nil {
+			return current, err
+		}
+
+		if err != nil {
+			// the token was published to some of the targets, and so is kept in
+			// place of the current token, and written to the rest on the next attempt
+			errs = append(errs, err)
+		}
+
+		log.Info("authorization issued", "resource", *id, "reason", reason)
+
+		if current != nil {
+			token.Retired = append(token.Retired, paradoxv1alpha1.RetiredToken{
+				ID:          *current,
+				DeleteAfter: metav1.NewTime(rotation.now.Add(rotation.gracePeriod)),
+			})
+		}
+
+		token.CreatedTime = &now
+		token.PermissionsHash = hash
+		token.RotateRequest = rotation.request
+		current = id
+	}
+
+	var retired []paradoxv1alpha1.RetiredToken
+	for _, rt := range token.Retired {
+		if rotation.now.Before(rt.DeleteAfter.Time) {
+			retired = append(retired, rt)
+			continue
+		}
+
+		if err := iclient.AuthorizationsAPI().DeleteAuthorizationWithID(ctx, string(rt.ID)); err != nil && !isInfluxNotFound(err) {
+			retired = append(retired, rt)
+			errs = append(errs, fmt.Errorf("deleting rotated authorization %s: %w", rt.ID, err))
+			continue
+		}
+
+		log.Info("rotated authorization deleted", "resource", rt.ID)
+	}
+
+	token.Retired = retired
 
-				return r.Client.Create(ctx, secret)
+	return current, utilerrors.NewAggregate(errs)
+}
+
+// issueToken creates a new authorization with permissions in the instance described
+// by delivery and publishes its token to every target. The authorization is deleted
+// again when its token cannot be published to any target, so that no untracked tokens
+// remain. Once published to some of the targets the token is in use, and so its
+// identifier is returned along with the error for the remaining targets.
+func (r *AuthorizationReconciler) issueToken(
+	ctx context.Context,
+	authorization *paradoxv1alpha1.Authorization,
+	delivery tokenDelivery,
+	iclient influxdb.Client,
+	orgID *paradoxv1alpha1.InfluxID,
+	permissions []domain.Permission,
+) (*paradoxv1alpha1.InfluxID, error) {
+	var (
+		authAPI     = iclient.AuthorizationsAPI()
+		description = authorizationDescription(authorization)
+	)
+
+	auth, err := authAPI.CreateAuthorization(ctx, &domain.Authorization{
+		AuthorizationUpdateRequest: domain.AuthorizationUpdateRequest{
+			Description: &description,
+		},
+		OrgID:       toStringPtr(orgID),
+		Permissions: &permissions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	written, err := r.writeTokenTargets(ctx, authorization, delivery, paradoxv1alpha1.InfluxID(*auth.Id), *auth.Token)
+	if err != nil && written == 0 {
+		if derr := authAPI.DeleteAuthorizationWithID(ctx, *auth.Id); derr != nil {
+			return nil, utilerrors.NewAggregate([]error{err, derr})
+		}
+
+		return nil, err
+	}
+
+	return fromStringPtr[paradoxv1alpha1.InfluxID](auth.Id), err
+}
+
+// recoverTokens finds authorizations carrying the marker of authorization which are
+// neither current nor retired, as left behind when a reconcile fails after creating an
+// authorization but before recording it in status. When there is no current
+// authorization, the most recent one with the desired permissions and a readable
+// token is adopted. All others are retired, to be deleted once the grace period ends.
+// It is only called when no current authorization, or no state for it, is recorded.
+func (r *AuthorizationReconciler) recoverTokens(
+	ctx context.Context,
+	authorization *paradoxv1alpha1.Authorization,
+	rotation tokenRotation,
+	delivery tokenDelivery,
+	iclient influxdb.Client,
+	orgID *paradoxv1alpha1.InfluxID,
+	hash string,
+	current *paradoxv1alpha1.InfluxID,
+	token *paradoxv1alpha1.TokenInstance,
+) (*paradoxv1alpha1.InfluxID, error) {
+	log := log.FromContext(ctx).WithValues("instance", delivery.data.Instance.Namespace+"/"+delivery.data.Instance.Name)
+
+	auths, err := iclient.AuthorizationsAPI().FindAuthorizationsByOrgID(ctx, string(*orgID))
+	if err != nil {
+		return current, err
+	}
+
+	known := map[paradoxv1alpha1.InfluxID]struct{}{}
+	if current != nil {
+		known[*current] = struct{}{}
+	}
+
+	for _, retired := range token.Retired {
+		known[retired.ID] = struct{}{}
+	}
+
+	marker := authorizationMarker(authorization)
+
+	var unknown []domain.Authorization
+	for _, auth := range fromPtr(auths) {
+		if auth.Id == nil || !strings.Contains(fromPtr(auth.Description), marker) {
+			continue
+		}
+
+		if _, ok := known[paradoxv1alpha1.InfluxID(*auth.Id)]; ok {
+			continue
+		}
+
+		unknown = append(unknown, auth)
+	}
+
+	// most recently created first
+	sort.Slice(unknown, func(i, j int) bool {
+		return fromPtr(unknown[i].CreatedAt).After(fromPtr(unknown[j].CreatedAt))
+	})
+
+	for _, auth := range unknown {
+		id := paradoxv1alpha1.InfluxID(*auth.Id)
+
+		if current == nil && fromPtr(auth.Token) != "" && permissionsHash(fromPtr(auth.Permissions)) == hash {
+			if _, err := r.writeTokenTargets(ctx, authorization, delivery, id, *auth.Token); err != nil {
+				return current, err
 			}
 
-			return nil
+			log.Info("unrecorded authorization adopted", "resource", id)
+
+			createdTime := metav1.NewTime(fromPtr(auth.CreatedAt))
+			token.CreatedTime = &createdTime
+			token.PermissionsHash = hash
+			token.RotateRequest = rotation.request
+			current = &id
+
+			continue
 		}
 
-		status.Instances.AddInstance(
-			instance,
-			authInstance.ID,
-		)
+		log.Info("unrecorded authorization retired", "resource", id)
 
-		return nil
-	}); err != nil {
-		log.Error(err, "error while configuring instances")
+		token.Retired = append(token.Retired, paradoxv1alpha1.RetiredToken{
+			ID:          id,
+			DeleteAfter: metav1.NewTime(rotation.now.Add(rotation.gracePeriod)),
+		})
+	}
 
-		return ctrl.Result{}, err
+	return current, nil
+}
+
+// domainPermissions resolves the permissions of authorization against the resources
+// recorded for the instance identified by namespace and name.
+func (r *AuthorizationReconciler) domainPermissions(
+	ctx context.Context,
+	authorization *paradoxv1alpha1.Authorization,
+	orgID *paradoxv1alpha1.InfluxID,
+	namespace, name string,
+) ([]domain.Permission, error) {
+	permissions := []domain.Permission{}
+
+	for _, permission := range authorization.Spec.Permissions {
+		perm, err := domainPermission(ctx, r.Client, authorization.Namespace, permission, orgID, types.NamespacedName{
+			Namespace: namespace,
+			Name:      name,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		permissions = append(permissions, perm)
 	}
 
-	if status.Instances != nil {
-		authorization.Status = status
+	return permissions, nil
+}
 
-		if err := r.Status().Update(ctx, &authorization); err != nil {
-			log.Error(err, "failed to update status")
+// updateStatus records instances along with the conditions derived from reconcileErr
+// in the status of authorization. The reconcile error is returned unless the status
+// update itself fails.
+func (r *AuthorizationReconciler) updateStatus(ctx context.Context, authorization *paradoxv1alpha1.Authorization, instances paradoxv1alpha1.Instances, reconcileErr error) error {
+	if instances == nil {
+		instances = paradoxv1alpha1.Instances{}
+	}
 
-			return ctrl.Result{}, err
+	authorization.Status.ObservedGeneration = authorization.Generation
+	authorization.Status.Instances = instances
+	setConditions(&authorization.Status.Conditions, authorization.Generation, instances, reconcileErr)
+
+	if err := r.Status().Update(ctx, authorization); err != nil {
+		log.FromContext(ctx).Error(err, "failed to update status")
+
+		return err
+	}
+
+	return reconcileErr
+}
+
+// deleteInstanceAuthorizations removes the authorization, along with any rotated
+// tokens, from every target instance in which it has previously been recorded.
+func (r *AuthorizationReconciler) deleteInstanceAuthorizations(ctx context.Context, authorization *paradoxv1alpha1.Authorization) error {
+	deletion := instanceDeletion{
+		Client:    r.Client,
+		Clients:   r.Clients,
+		Recorder:  r.Recorder,
+		Object:    authorization,
+		Noun:      "authorization",
+		Instances: authorization.Status.Instances,
+	}
+
+	return deletion.deleteWithin(ctx, authorization.Spec.Organization, func(instance *paradoxv1alpha1.Instance, iclient influxdb.Client, id paradoxv1alpha1.InfluxID) error {
+		ids := []paradoxv1alpha1.InfluxID{id}
+		for _, retired := range authorization.Status.Tokens.Get(instance.ObjectMeta.Namespace, instance.ObjectMeta.Name).Retired {
+			ids = append(ids, retired.ID)
 		}
 
-		log.V(4).Info("status updated")
+		for _, id := range ids {
+			if err := iclient.AuthorizationsAPI().DeleteAuthorizationWithID(ctx, string(id)); err != nil && !isInfluxNotFound(err) {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// checkAuthorization reports whether the authorization identified by id still
+// exists in the instance, reactivating it when it has been deactivated.
+func checkAuthorization(ctx context.Context, iclient influxdb.Client, id paradoxv1alpha1.InfluxID) (bool, error) {
+	resp, err := domain.NewClientWithResponses(iclient.HTTPService()).
+		GetAuthorizationsIDWithResponse(ctx, string(id), &domain.GetAuthorizationsIDParams{})
+	if err != nil {
+		return false, err
+	}
+
+	if resp.StatusCode() == nethttp.StatusNotFound {
+		return false, nil
+	}
+
+	if resp.JSONDefault != nil {
+		return false, domain.ErrorToHTTPError(resp.JSONDefault, resp.StatusCode())
+	}
+
+	if resp.JSON200 == nil {
+		return false, fmt.Errorf("%w: get authorization returned %s", ErrInfluxUnexpectedResponse, resp.Status())
+	}
+
+	if status := resp.JSON200.Status; status != nil && *status != domain.AuthorizationUpdateRequestStatusActive {
+		if _, err := iclient.AuthorizationsAPI().UpdateAuthorizationStatusWithID(ctx, string(id), domain.AuthorizationUpdateRequestStatusActive); err != nil {
+			return true, err
+		}
 	}
 
-	return ctrl.Result{}, nil
+	return true, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *AuthorizationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.Authorization{}, orgField, func(rawObj client.Object) []string {
+		authorization := rawObj.(*paradoxv1alpha1.Authorization)
+		if authorization.Spec.Organization == "" {
+			return nil
+		}
+
+		return []string{authorization.Spec.Organization}
+	}); err != nil {
+		return err
+	}
+
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.Authorization{}, permissionResourceField, func(rawObj client.Object) []string {
+		authorization := rawObj.(*paradoxv1alpha1.Authorization)
+
+		var keys []string
+		for _, permission := range authorization.Spec.Permissions {
+			if permission.Resource.Name != "" {
+				keys = append(keys, permissionResourceKey(permission.Resource.ResourceType, permission.Resource.Name))
+			}
+		}
+
+		return keys
+	}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&paradoxv1alpha1.Authorization{}).
+		For(&paradoxv1alpha1.Authorization{}, builder.WithPredicates(specOrAnnotationChanged())).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Organization{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.AuthorizationList{}, orgField)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Bucket{}},
+			handler.EnqueueRequestsFromMapFunc(r.findAuthorizationsForResource(paradoxv1alpha1.ResourceTypeBuckets)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Task{}},
+			handler.EnqueueRequestsFromMapFunc(r.findAuthorizationsForResource(paradoxv1alpha1.ResourceTypeTasks)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Check{}},
+			handler.EnqueueRequestsFromMapFunc(r.findAuthorizationsForResource(paradoxv1alpha1.ResourceTypeChecks)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.NotificationEndpoint{}},
+			handler.EnqueueRequestsFromMapFunc(r.findAuthorizationsForResource(paradoxv1alpha1.ResourceTypeNotificationEndpoints)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.NotificationRule{}},
+			handler.EnqueueRequestsFromMapFunc(r.findAuthorizationsForResource(paradoxv1alpha1.ResourceTypeNotificationRules)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Dashboard{}},
+			handler.EnqueueRequestsFromMapFunc(r.findAuthorizationsForResource(paradoxv1alpha1.ResourceTypeDashboards)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Label{}},
+			handler.EnqueueRequestsFromMapFunc(r.findAuthorizationsForResource(paradoxv1alpha1.ResourceTypeLabels)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.User{}},
+			handler.EnqueueRequestsFromMapFunc(r.findAuthorizationsForResource(paradoxv1alpha1.ResourceTypeUsers)),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.findAuthorizationForTarget),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.findAuthorizationForTarget),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.Namespace{}},
+			handler.EnqueueRequestsFromMapFunc(r.findAuthorizationsForNamespace),
+		).
 		Complete(r)
 }
+
+// findAuthorizationsForResource returns a map function which requests every
+// authorization granting a permission on the named resource of resourceType.
+func (r *AuthorizationReconciler) findAuthorizationsForResource(resourceType paradoxv1alpha1.ResourceType) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		var authorizations paradoxv1alpha1.AuthorizationList
+		if err := r.List(context.TODO(), &authorizations,
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{permissionResourceField: permissionResourceKey(resourceType, obj.GetName())},
+		); err != nil {
+			return []reconcile.Request{}
+		}
+
+		requests := make([]reconcile.Request, len(authorizations.Items))
+		for i, authorization := range authorizations.Items {
+			requests[i] = reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&authorization)}
+		}
+
+		return requests
+	}
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// fakeInfluxClient is an Influx client whose APIs are replaced by fakes.
+// Calls to any other API panic.
+type fakeInfluxClient struct {
+	influxdb.Client
+	authorizations api.AuthorizationsAPI
+}
+
+func (c fakeInfluxClient) AuthorizationsAPI() api.AuthorizationsAPI {
+	return c.authorizations
+}
+
+// fakeAuthorizationsAPI lists a fixed set of authorizations, to which created
+// authorizations are added, and records the identifiers of deleted authorizations.
+type fakeAuthorizationsAPI struct {
+	api.AuthorizationsAPI
+	authorizations []domain.Authorization
+	deleted        []string
+	err            error
+}
+
+func (a *fakeAuthorizationsAPI) CreateAuthorization(ctx context.Context, authorization *domain.Authorization) (*domain.Authorization, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	id, token := "created", "created-token"
+	created := *authorization
+	created.Id, created.Token = &id, &token
+	a.authorizations = append(a.authorizations, created)
+
+	return &created, nil
+}
+
+func (a *fakeAuthorizationsAPI) DeleteAuthorizationWithID(ctx context.Context, authorizationID string) error {
+	a.deleted = append(a.deleted, authorizationID)
+
+	return nil
+}
+
+func (a *fakeAuthorizationsAPI) FindAuthorizationsByOrgID(ctx context.Context, orgID string) (*[]domain.Authorization, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	return &a.authorizations, nil
+}
+
+func TestRecoverTokens(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	rotation := tokenRotation{now: now, gracePeriod: time.Hour, request: "1"}
+
+	authorization := tokenSecretAuthorization("default", "telegraf-{{ .Instance.Name }}")
+	marker := authorizationDescription(authorization)
+
+	str := func(s string) *string { return &s }
+	desired := []domain.Permission{{Action: domain.PermissionActionRead, Resource: domain.Resource{Type: domain.ResourceTypeBuckets, OrgID: str("0000000000000001")}}}
+	other := []domain.Permission{{Action: domain.PermissionActionWrite, Resource: domain.Resource{Type: domain.ResourceTypeBuckets, OrgID: str("0000000000000001")}}}
+
+	influxAuthorization := func(id, description, token string, permissions []domain.Permission, created time.Time) domain.Authorization {
+		auth := domain.Authorization{
+			Id:          str(id),
+			Token:       str(token),
+			Permissions: &permissions,
+			CreatedAt:   &created,
+		}
+		auth.Description = str(description)
+
+		return auth
+	}
+
+	listed := []domain.Authorization{
+		influxAuthorization("a1", marker, "t1", desired, now.Add(-3*time.Hour)),
+		influxAuthorization("a2", marker, "t2", desired, now.Add(-2*time.Hour)),
+		influxAuthorization("a3", marker, "t3", other, now.Add(-time.Hour)),
+		influxAuthorization("a4", "another authorization", "t4", desired, now),
+		influxAuthorization("current", marker, "t5", desired, now.Add(-4*time.Hour)),
+		influxAuthorization("retired", marker, "t6", desired, now.Add(-5*time.Hour)),
+	}
+
+	unreadable := append([]domain.Authorization(nil), listed...)
+	unreadable[1].Token = str("")
+
+	current := paradoxv1alpha1.InfluxID("current")
+
+	tests := []struct {
+		name        string
+		listed      []domain.Authorization
+		listErr     error
+		current     *paradoxv1alpha1.InfluxID
+		wantCurrent *paradoxv1alpha1.InfluxID
+		wantToken   string
+		wantRetired []paradoxv1alpha1.InfluxID
+		wantErr     bool
+	}{
+		{
+			name:        "most recent with the desired permissions adopted",
+			listed:      listed,
+			wantCurrent: idPtr("a2"),
+			wantToken:   "t2",
+			wantRetired: []paradoxv1alpha1.InfluxID{"a1", "a3", "current"},
+		},
+		{
+			name:        "unreadable tokens are not adopted",
+			listed:      unreadable,
+			wantCurrent: idPtr("a1"),
+			wantToken:   "t1",
+			wantRetired: []paradoxv1alpha1.InfluxID{"a2", "a3", "current"},
+		},
+		{
+			name:        "unrecorded retired alongside current",
+			listed:      listed,
+			current:     &current,
+			wantCurrent: &current,
+			wantRetired: []paradoxv1alpha1.InfluxID{"a1", "a2", "a3"},
+		},
+		{
+			name:    "listing fails",
+			listErr: errors.New("unavailable"),
+			current: &current,
+			wantErr: true,
+			// the recorded state is left untouched
+			wantCurrent: &current,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeClient(t)
+			r := &AuthorizationReconciler{Client: c, Scheme: c.Scheme()}
+			iclient := fakeInfluxClient{authorizations: &fakeAuthorizationsAPI{authorizations: tt.listed, err: tt.listErr}}
+
+			token := &paradoxv1alpha1.TokenInstance{
+				Retired: []paradoxv1alpha1.RetiredToken{{ID: "retired"}},
+			}
+			if tt.current != nil {
+				token.PermissionsHash = permissionsHash(desired)
+			}
+
+			orgID := paradoxv1alpha1.InfluxID("0000000000000001")
+
+			delivery, err := r.resolveTokenDelivery(context.Background(), authorization, tokenSecretTemplateData())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := r.recoverTokens(context.Background(), authorization, rotation, delivery, iclient, &orgID, permissionsHash(desired), tt.current, token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("recoverTokens() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.wantCurrent) {
+				t.Errorf("recoverTokens() current = %v, want %v", fromPtr(got), fromPtr(tt.wantCurrent))
+			}
+
+			var retired []paradoxv1alpha1.InfluxID
+			for _, rt := range token.Retired[1:] {
+				retired = append(retired, rt.ID)
+
+				if !rt.DeleteAfter.Time.Equal(now.Add(time.Hour)) {
+					t.Errorf("retired %s deleted after %v, want %v", rt.ID, rt.DeleteAfter, now.Add(time.Hour))
+				}
+			}
+
+			sort.Slice(retired, func(i, j int) bool { return retired[i] < retired[j] })
+			if !reflect.DeepEqual(retired, tt.wantRetired) {
+				t.Errorf("retired %v, want %v", retired, tt.wantRetired)
+			}
+
+			var secret corev1.Secret
+			err = c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "telegraf-primary"}, &secret)
+			if tt.wantToken == "" {
+				if err == nil {
+					t.Errorf("token secret written with %q", secret.Data["token"])
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("token secret: %v", err)
+			}
+
+			if got := string(secret.Data["token"]); got != tt.wantToken {
+				t.Errorf("token secret holds %q, want %q", got, tt.wantToken)
+			}
+
+			if token.PermissionsHash != permissionsHash(desired) || token.RotateRequest != rotation.request || token.CreatedTime == nil {
+				t.Errorf("adopted token recorded as %+v", token)
+			}
+		})
+	}
+}
+
+func idPtr(id string) *paradoxv1alpha1.InfluxID {
+	influxID := paradoxv1alpha1.InfluxID(id)
+	return &influxID
+}
+
+func TestIssueToken(t *testing.T) {
+	unmanaged := func(name string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+	}
+
+	tests := []struct {
+		name        string
+		existing    []client.Object
+		wantID      *paradoxv1alpha1.InfluxID
+		wantErr     bool
+		wantDeleted []string
+	}{
+		{
+			name:   "published to every target",
+			wantID: idPtr("created"),
+		},
+		{
+			name:     "published to some targets",
+			existing: []client.Object{unmanaged("telegraf-primary")},
+			wantID:   idPtr("created"),
+			wantErr:  true,
+		},
+		{
+			name:        "published to no target",
+			existing:    []client.Object{unmanaged("telegraf-primary"), unmanaged("metrics-primary")},
+			wantErr:     true,
+			wantDeleted: []string{"created"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeClient(t, tt.existing...)
+			r := &AuthorizationReconciler{Client: c, Scheme: c.Scheme()}
+			authorizations := &fakeAuthorizationsAPI{}
+
+			authorization := tokenSecretAuthorization("default", "telegraf-{{ .Instance.Name }}")
+			authorization.Spec.Token.Secrets = []paradoxv1alpha1.SecretSpec{{
+				TargetSpec: paradoxv1alpha1.TargetSpec{Namespace: "default", NameTemplate: "metrics-{{ .Instance.Name }}"},
+				Key:        "token",
+			}}
+
+			delivery, err := r.resolveTokenDelivery(context.Background(), authorization, tokenSecretTemplateData())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			orgID := paradoxv1alpha1.InfluxID("0000000000000001")
+
+			got, err := r.issueToken(context.Background(), authorization, delivery, fakeInfluxClient{authorizations: authorizations}, &orgID, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("issueToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.wantID) {
+				t.Errorf("issueToken() = %v, want %v", fromPtr(got), fromPtr(tt.wantID))
+			}
+
+			if !reflect.DeepEqual(authorizations.deleted, tt.wantDeleted) {
+				t.Errorf("deleted authorizations %v, want %v", authorizations.deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
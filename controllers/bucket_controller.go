@@ -19,19 +19,24 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	influxdb "github.com/influxdata/influxdb-client-go/v2"
 	"github.com/influxdata/influxdb-client-go/v2/domain"
-	"k8s.io/apimachinery/pkg/fields"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
@@ -44,7 +49,14 @@ const (
 // BucketReconciler reconciles a Bucket object
 type BucketReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Clients is the pool of Influx clients shared by every reconciler.
+	Clients *ClientPool
+
+	// ResyncInterval is the interval at which each bucket is reconciled
+	// against its target instances, unless overridden by annotation.
+	ResyncInterval time.Duration
 }
 
 //+kubebuilder:rbac:groups=paradox.macro.re,resources=buckets,verbs=get;list;watch;create;update;patch;delete
@@ -53,9 +65,14 @@ type BucketReconciler struct {
 
 //+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations,verbs=get
 //+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations/status,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=labels,verbs=get;list;watch
+
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
+// Buckets which already exist are compared against the spec and any drift in
+// their description, retention or shard group duration is corrected.
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
@@ -71,6 +88,24 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	log = log.WithValues("bucket", bucket)
 
+	if !bucket.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := finalize(ctx, r.Client, &bucket, bucket.Spec.DeletionPolicy, func() error {
+			return r.deleteInstanceBuckets(ctx, &bucket)
+		}); err != nil {
+			log.Error(err, "failed to finalize bucket")
+
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if err := syncFinalizer(ctx, r.Client, &bucket, bucket.Spec.DeletionPolicy); err != nil {
+		log.Error(err, "failed to update finalizers")
+
+		return ctrl.Result{}, err
+	}
+
 	var organization paradoxv1alpha1.Organization
 	if err := r.Get(ctx, types.NamespacedName{
 		Namespace: req.NamespacedName.Namespace,
@@ -78,95 +113,272 @@ func (r *BucketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}, &organization); err != nil {
 		log.Error(err, "unable to fetch organization")
 
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		return ctrl.Result{}, client.IgnoreNotFound(r.updateStatus(ctx, &bucket, bucket.Status.Instances, bucket.Status.LabelMappings, fmt.Errorf("organization %q: %w", bucket.Spec.Organization, err)))
 	}
 
-	status := paradoxv1alpha1.BucketStatus{
-		Instances: paradoxv1alpha1.Instances{},
+	desired, err := domainBucket(nil, bucket)
+	if err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, &bucket, bucket.Status.Instances, bucket.Status.LabelMappings, err)
 	}
 
-	if err := forEachInstanceClient(ctx, r.Client, &organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) error {
+	labels, err := fetchLabels(ctx, r.Client, req.NamespacedName.Namespace, bucket.Spec.Organization, bucket.Spec.Labels)
+	if err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, &bucket, bucket.Status.Instances, bucket.Status.LabelMappings, err)
+	}
+
+	var (
+		mu           sync.Mutex
+		drift        []string
+		incompatible []string
+		mappings     = newLabelMappings(bucket.Status.LabelMappings)
+	)
+
+	instances, err := reconcileInstances(ctx, r.Client, r.Clients, &organization, bucket.Status.Instances, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) (*paradoxv1alpha1.InfluxID, error) {
 		namespace, name := instance.ObjectMeta.Namespace, instance.ObjectMeta.Name
-		wrapErr := func(err error) error {
-			return fmt.Errorf("influx instance '%s/%s': %w", namespace, name, err)
-		}
+
+		var changes []string
 
 		bucketAPI := client.BucketsAPI()
 		bkt, err := bucketAPI.FindBucketByName(ctx, bucket.Spec.Name)
 		if err != nil {
-			if !strings.Contains(err.Error(), "not found") {
-				return wrapErr(err)
+			if !isInfluxNotFound(err) {
+				return nil, err
 			}
 
 			orgInstance := organization.Status.Instances[namespace][name]
 
 			// create bucket if not exists
 
-			bkt, err = bucketAPI.CreateBucket(ctx, domainBucket(orgInstance.ID, bucket))
+			create := *desired
+			create.OrgID = toStringPtr(orgInstance.ID)
+
+			bkt, err = bucketAPI.CreateBucket(ctx, &create)
 			if err != nil {
-				return wrapErr(err)
+				return nil, err
 			}
+		} else if changes = bucketDrift(bkt, desired); len(changes) > 0 {
+			// update bucket if it exists and differs
 
-			status.Instances.AddInstance(
-				instance,
-				fromStringPtr[paradoxv1alpha1.InfluxID](bkt.Id),
-			)
+			bkt.Description = desired.Description
+			bkt.RetentionRules = desired.RetentionRules
+			bkt, err = bucketAPI.UpdateBucket(ctx, bkt)
+			if err != nil {
+				return nil, err
+			}
+		}
 
-			return nil
+		labelDrift, err := labels.syncLabelMappings(ctx, client.HTTPService(), instance, "buckets", paradoxv1alpha1.InfluxID(fromPtr(bkt.Id)), mappings)
+		changes = append(changes, labelDrift...)
+
+		if len(changes) > 0 {
+			message := fmt.Sprintf("corrected drift in instance %s/%s: %s", namespace, name, strings.Join(changes, ", "))
+			r.Recorder.Event(&bucket, corev1.EventTypeNormal, "DriftCorrected", message)
+
+			mu.Lock()
+			drift = append(drift, message)
+			mu.Unlock()
 		}
 
-		// update bucket if it exists and differs
+		if err != nil {
+			return fromStringPtr[paradoxv1alpha1.InfluxID](bkt.Id), err
+		}
 
-		if bkt.Description == nil || *bkt.Description != bucket.Spec.Description {
-			bkt.Description = &bucket.Spec.Description
-			bkt, err = bucketAPI.UpdateBucket(ctx, bkt)
-			if err != nil {
-				return wrapErr(err)
-			}
+		incompatibleChanges, err := reconcileBucketSchema(ctx, client, bkt, bucket.Spec)
+		if err != nil {
+			return nil, err
 		}
 
-		status.Instances.AddInstance(
-			instance,
-			fromStringPtr[paradoxv1alpha1.InfluxID](bkt.Id),
-		)
+		if len(incompatibleChanges) > 0 {
+			message := fmt.Sprintf("incompatible schema in instance %s/%s: %s", namespace, name, strings.Join(incompatibleChanges, ", "))
+			r.Recorder.Event(&bucket, corev1.EventTypeWarning, "IncompatibleSchema", message)
 
-		return nil
-	}); err != nil {
+			mu.Lock()
+			incompatible = append(incompatible, message)
+			mu.Unlock()
+		}
+
+		return fromStringPtr[paradoxv1alpha1.InfluxID](bkt.Id), nil
+	})
+	if err != nil {
 		log.Error(err, "error while configuring instances")
+	}
 
-		return ctrl.Result{}, err
+	setDriftCondition(&bucket.Status.Conditions, bucket.Generation, drift)
+
+	schemaCondition := metav1.Condition{
+		Type:               paradoxv1alpha1.ConditionSchemaCompatible,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: bucket.Generation,
+		Reason:             "Compatible",
 	}
 
-	bucket.Status = status
+	if len(incompatible) > 0 {
+		sort.Strings(incompatible)
 
-	if err := r.Status().Update(ctx, &bucket); err != nil {
-		log.Error(err, "failed to update status")
+		schemaCondition.Status = metav1.ConditionFalse
+		schemaCondition.Reason = "IncompatibleChanges"
+		schemaCondition.Message = strings.Join(incompatible, "; ")
+	}
 
-		return ctrl.Result{}, err
+	meta.SetStatusCondition(&bucket.Status.Conditions, schemaCondition)
+
+	return resyncResult(ctx, &bucket, r.ResyncInterval), r.updateStatus(ctx, &bucket, instances, mappings.assigned, err)
+}
+
+// updateStatus records instances and label mappings along with the conditions
+// derived from reconcileErr in the status of bucket. The reconcile error is returned
+// unless the status update itself fails.
+func (r *BucketReconciler) updateStatus(ctx context.Context, bucket *paradoxv1alpha1.Bucket, instances paradoxv1alpha1.Instances, mappings paradoxv1alpha1.InstanceLabels, reconcileErr error) error {
+	if instances == nil {
+		instances = paradoxv1alpha1.Instances{}
+	}
+
+	// label mappings are only kept for instances which are still recorded
+	mappings.Retain(instances)
+
+	bucket.Status.ObservedGeneration = bucket.Generation
+	bucket.Status.Instances = instances
+	bucket.Status.LabelMappings = mappings
+	setConditions(&bucket.Status.Conditions, bucket.Generation, instances, reconcileErr)
+
+	if err := r.Status().Update(ctx, bucket); err != nil {
+		log.FromContext(ctx).Error(err, "failed to update status")
+
+		return err
 	}
 
-	return ctrl.Result{}, nil
+	return reconcileErr
+}
+
+// deleteInstanceBuckets removes the bucket from every target instance in which
+// it has previously been recorded.
+func (r *BucketReconciler) deleteInstanceBuckets(ctx context.Context, bucket *paradoxv1alpha1.Bucket) error {
+	deletion := instanceDeletion{
+		Client:    r.Client,
+		Clients:   r.Clients,
+		Recorder:  r.Recorder,
+		Object:    bucket,
+		Noun:      "bucket",
+		Instances: bucket.Status.Instances,
+	}
+
+	return deletion.deleteWithin(ctx, bucket.Spec.Organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client, id paradoxv1alpha1.InfluxID) error {
+		if err := client.BucketsAPI().DeleteBucketWithID(ctx, string(id)); err != nil && !isInfluxNotFound(err) {
+			return err
+		}
+
+		return nil
+	})
 }
 
-func domainBucket(orgID *paradoxv1alpha1.InfluxID, bucket paradoxv1alpha1.Bucket) *domain.Bucket {
-	var rr domain.RetentionRules
+// domainBucket returns the Influx representation of bucket, owned by the
+// organization identified by orgID.
+func domainBucket(orgID *paradoxv1alpha1.InfluxID, bucket paradoxv1alpha1.Bucket) (*domain.Bucket, error) {
+	rule := domain.RetentionRule{
+		Type: domain.RetentionRuleTypeExpire,
+	}
+
 	if bucket.Spec.RetentionPolicy != "" {
 		dur, err := time.ParseDuration(bucket.Spec.RetentionPolicy)
-		if err == nil {
-			rr = domain.RetentionRules{
-				domain.RetentionRule{
-					EverySeconds: int64(dur.Seconds()),
-				},
-			}
+		if err != nil {
+			return nil, fmt.Errorf("parsing retention policy: %w", err)
+		}
+
+		rule.EverySeconds = int64(dur.Seconds())
+	}
+
+	if bucket.Spec.ShardGroupDuration != "" {
+		dur, err := time.ParseDuration(bucket.Spec.ShardGroupDuration)
+		if err != nil {
+			return nil, fmt.Errorf("parsing shard group duration: %w", err)
 		}
+
+		seconds := int64(dur.Seconds())
+		rule.ShardGroupDurationSeconds = &seconds
 	}
 
-	return &domain.Bucket{
+	desired := &domain.Bucket{
 		Name:           bucket.Spec.Name,
 		OrgID:          toStringPtr(orgID),
 		Description:    &bucket.Spec.Description,
-		RetentionRules: rr,
+		RetentionRules: domain.RetentionRules{rule},
+	}
+
+	if bucket.Spec.SchemaType != "" {
+		schemaType := domain.SchemaType(bucket.Spec.SchemaType)
+		desired.SchemaType = &schemaType
+	}
+
+	return desired, nil
+}
+
+// reconcileBucketSchema applies the measurement schemas of spec to the existing
+// bucket, returning each change which cannot be applied. The schema type of a
+// bucket is fixed on creation, and measurement schemas only apply to buckets
+// with an explicit schema type.
+func reconcileBucketSchema(ctx context.Context, client influxdb.Client, bkt *domain.Bucket, spec paradoxv1alpha1.BucketSpec) ([]string, error) {
+	existing := paradoxv1alpha1.SchemaTypeImplicit
+	if bkt.SchemaType != nil {
+		existing = paradoxv1alpha1.SchemaType(*bkt.SchemaType)
+	}
+
+	desired := spec.SchemaType
+	if desired == "" {
+		desired = paradoxv1alpha1.SchemaTypeImplicit
+	}
+
+	if existing != desired {
+		return []string{fmt.Sprintf("schema type cannot change from %s to %s", existing, desired)}, nil
+	}
+
+	if desired != paradoxv1alpha1.SchemaTypeExplicit {
+		if len(spec.MeasurementSchemas) > 0 {
+			return []string{"measurement schemas require an explicit schema type"}, nil
+		}
+
+		return nil, nil
+	}
+
+	return reconcileMeasurementSchemas(ctx, client, fromPtr(bkt.OrgID), fromPtr(bkt.Id), spec.MeasurementSchemas)
+}
+
+// bucketDrift describes each difference between the existing bucket and the
+// desired bucket. The shard group duration is only compared when desired
+// declares one, as the instance otherwise chooses its own.
+func bucketDrift(existing, desired *domain.Bucket) (changes []string) {
+	if fromPtr(existing.Description) != fromPtr(desired.Description) {
+		changes = append(changes, fmt.Sprintf("description %q -> %q", fromPtr(existing.Description), fromPtr(desired.Description)))
+	}
+
+	// no retention rules means data never expires
+	var existingRule domain.RetentionRule
+	if len(existing.RetentionRules) > 0 {
+		existingRule = existing.RetentionRules[0]
+	}
+
+	desiredRule := desired.RetentionRules[0]
+
+	if existingRule.EverySeconds != desiredRule.EverySeconds {
+		changes = append(changes, fmt.Sprintf("retention %s -> %s",
+			formatRetention(existingRule.EverySeconds), formatRetention(desiredRule.EverySeconds)))
 	}
+
+	if desiredRule.ShardGroupDurationSeconds != nil &&
+		fromPtr(existingRule.ShardGroupDurationSeconds) != *desiredRule.ShardGroupDurationSeconds {
+		changes = append(changes, fmt.Sprintf("shard group duration %s -> %s",
+			formatRetention(fromPtr(existingRule.ShardGroupDurationSeconds)), formatRetention(*desiredRule.ShardGroupDurationSeconds)))
+	}
+
+	return changes
+}
+
+// formatRetention formats a duration in seconds, where zero means infinite.
+func formatRetention(seconds int64) string {
+	if seconds == 0 {
+		return "infinite"
+	}
+
+	return (time.Duration(seconds) * time.Second).String()
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -182,32 +394,21 @@ func (r *BucketReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
+	if err := indexLabels(mgr, &paradoxv1alpha1.Bucket{}, func(rawObj client.Object) []string {
+		return rawObj.(*paradoxv1alpha1.Bucket).Spec.Labels
+	}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&paradoxv1alpha1.Bucket{}).
+		For(&paradoxv1alpha1.Bucket{}, builder.WithPredicates(specOrAnnotationChanged())).
 		Watches(
 			&source.Kind{Type: &paradoxv1alpha1.Organization{}},
-			handler.EnqueueRequestsFromMapFunc(r.findObjectsForOrganization),
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.BucketList{}, orgField)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Label{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.BucketList{}, labelsField)),
 		).
 		Complete(r)
 }
-
-func (r *BucketReconciler) findObjectsForOrganization(org client.Object) []reconcile.Request {
-	associatedBuckets := &paradoxv1alpha1.BucketList{}
-	if err := r.List(context.TODO(), associatedBuckets, &client.ListOptions{
-		FieldSelector: fields.OneTermEqualSelector(orgField, org.GetName()),
-		Namespace:     org.GetNamespace(),
-	}); err != nil {
-		return []reconcile.Request{}
-	}
-
-	requests := make([]reconcile.Request, len(associatedBuckets.Items))
-	for i, item := range associatedBuckets.Items {
-		requests[i] = reconcile.Request{
-			NamespacedName: types.NamespacedName{
-				Name:      item.GetName(),
-				Namespace: item.GetNamespace(),
-			},
-		}
-	}
-	return requests
-}
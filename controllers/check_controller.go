@@ -0,0 +1,326 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// CheckReconciler reconciles a Check object
+type CheckReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Clients is the pool of Influx clients shared by every reconciler.
+	Clients *ClientPool
+	// ResyncInterval is the interval at which each check is reconciled
+	// against its target instances, unless overridden by annotation.
+	ResyncInterval time.Duration
+}
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=checks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=checks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=checks/finalizers,verbs=update
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations/status,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=labels,verbs=get;list;watch
+
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// Checks which already exist are compared against the spec and any drift is corrected.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *CheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var check paradoxv1alpha1.Check
+	if err := r.Get(ctx, req.NamespacedName, &check); err != nil {
+		log.Error(err, "unable to fetch check")
+
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	log = log.WithValues("check", check)
+
+	if !check.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := finalize(ctx, r.Client, &check, check.Spec.DeletionPolicy, func() error {
+			return r.deleteInstanceChecks(ctx, &check)
+		}); err != nil {
+			log.Error(err, "failed to finalize check")
+
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if err := syncFinalizer(ctx, r.Client, &check, check.Spec.DeletionPolicy); err != nil {
+		log.Error(err, "failed to update finalizers")
+
+		return ctrl.Result{}, err
+	}
+
+	var organization paradoxv1alpha1.Organization
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: req.NamespacedName.Namespace,
+		Name:      check.Spec.Organization,
+	}, &organization); err != nil {
+		log.Error(err, "unable to fetch organization")
+
+		return ctrl.Result{}, client.IgnoreNotFound(r.updateStatus(ctx, &check, check.Status.Instances, check.Status.LabelMappings, fmt.Errorf("organization %q: %w", check.Spec.Organization, err)))
+	}
+
+	desired, err := influxCheck(check.Spec)
+	if err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, &check, check.Status.Instances, check.Status.LabelMappings, err)
+	}
+
+	labels, err := fetchLabels(ctx, r.Client, req.NamespacedName.Namespace, check.Spec.Organization, check.Spec.Labels)
+	if err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, &check, check.Status.Instances, check.Status.LabelMappings, err)
+	}
+
+	var (
+		mu       sync.Mutex
+		drift    []string
+		mappings = newLabelMappings(check.Status.LabelMappings)
+	)
+
+	instances, err := reconcileInstances(ctx, r.Client, r.Clients, &organization, check.Status.Instances, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) (*paradoxv1alpha1.InfluxID, error) {
+		namespace, name := instance.ObjectMeta.Namespace, instance.ObjectMeta.Name
+
+		orgID, err := organizationID(&organization, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		previous := check.Status.Instances[namespace][name].ID
+
+		id, changes, err := checksResource.sync(ctx, client.HTTPService(), previous, orgID, desired, false)
+		if err != nil {
+			return id, err
+		}
+
+		labelDrift, err := labels.syncLabelMappings(ctx, client.HTTPService(), instance, checksResource.path, *id, mappings)
+		if err != nil {
+			return id, err
+		}
+
+		changes = append(changes, labelDrift...)
+
+		if len(changes) > 0 {
+			message := fmt.Sprintf("corrected drift in instance %s/%s: %s", namespace, name, strings.Join(changes, ", "))
+			r.Recorder.Event(&check, corev1.EventTypeNormal, "DriftCorrected", message)
+
+			mu.Lock()
+			drift = append(drift, message)
+			mu.Unlock()
+		}
+
+		return id, nil
+	})
+	if err != nil {
+		log.Error(err, "error while configuring instances")
+	}
+
+	setDriftCondition(&check.Status.Conditions, check.Generation, drift)
+
+	return resyncResult(ctx, &check, r.ResyncInterval), r.updateStatus(ctx, &check, instances, mappings.assigned, err)
+}
+
+// updateStatus records instances and label mappings along with the conditions
+// derived from reconcileErr in the status of check. The reconcile error is returned
+// unless the status update itself fails.
+func (r *CheckReconciler) updateStatus(ctx context.Context, check *paradoxv1alpha1.Check, instances paradoxv1alpha1.Instances, mappings paradoxv1alpha1.InstanceLabels, reconcileErr error) error {
+	if instances == nil {
+		instances = paradoxv1alpha1.Instances{}
+	}
+
+	// label mappings are only kept for instances which are still recorded
+	mappings.Retain(instances)
+
+	check.Status.ObservedGeneration = check.Generation
+	check.Status.Instances = instances
+	check.Status.LabelMappings = mappings
+	setConditions(&check.Status.Conditions, check.Generation, instances, reconcileErr)
+
+	if err := r.Status().Update(ctx, check); err != nil {
+		log.FromContext(ctx).Error(err, "failed to update status")
+
+		return err
+	}
+
+	return reconcileErr
+}
+
+// deleteInstanceChecks removes the check from every target instance in which
+// it has previously been recorded.
+func (r *CheckReconciler) deleteInstanceChecks(ctx context.Context, check *paradoxv1alpha1.Check) error {
+	deletion := instanceDeletion{
+		Client:    r.Client,
+		Clients:   r.Clients,
+		Recorder:  r.Recorder,
+		Object:    check,
+		Noun:      "check",
+		Instances: check.Status.Instances,
+	}
+
+	return deletion.deleteWithin(ctx, check.Spec.Organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client, id paradoxv1alpha1.InfluxID) error {
+		return checksResource.delete(ctx, client.HTTPService(), id)
+	})
+}
+
+// influxCheck returns the Influx representation of the check defined by spec.
+func influxCheck(spec paradoxv1alpha1.CheckSpec) (influxObject, error) {
+	tags := make([]influxObject, 0, len(spec.Tags))
+	for key, value := range spec.Tags {
+		tags = append(tags, influxObject{"key": key, "value": value})
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i]["key"].(string) < tags[j]["key"].(string)
+	})
+
+	check := influxObject{
+		"name":        spec.Name,
+		"description": spec.Description,
+		"status":      string(activityStatus(spec.Status)),
+		"type":        string(spec.Type()),
+		"query": influxObject{
+			"text":     spec.Query,
+			"editMode": "advanced",
+		},
+		"every":                 spec.Every,
+		"offset":                spec.Offset,
+		"statusMessageTemplate": spec.StatusMessageTemplate,
+		"tags":                  tags,
+	}
+
+	switch {
+	case spec.Threshold != nil:
+		thresholds := make([]influxObject, len(spec.Threshold.Thresholds))
+		for i, threshold := range spec.Threshold.Thresholds {
+			obj := influxObject{
+				"type":      string(threshold.Type),
+				"level":     string(threshold.Level),
+				"allValues": threshold.AllValues,
+			}
+
+			if threshold.Type == paradoxv1alpha1.ThresholdTypeRange {
+				obj["within"] = threshold.Within
+				for key, value := range map[string]string{"min": threshold.Min, "max": threshold.Max} {
+					number, err := strconv.ParseFloat(value, 64)
+					if err != nil {
+						return nil, fmt.Errorf("threshold %d %s: %w", i, key, err)
+					}
+
+					obj[key] = number
+				}
+			} else {
+				number, err := strconv.ParseFloat(threshold.Value, 64)
+				if err != nil {
+					return nil, fmt.Errorf("threshold %d value: %w", i, err)
+				}
+
+				obj["value"] = number
+			}
+
+			thresholds[i] = obj
+		}
+
+		check["thresholds"] = thresholds
+	case spec.Deadman != nil:
+		level := spec.Deadman.Level
+		if level == "" {
+			level = paradoxv1alpha1.CheckLevelCrit
+		}
+
+		check["timeSince"] = spec.Deadman.TimeSince
+		check["staleTime"] = spec.Deadman.StaleTime
+		check["reportZero"] = spec.Deadman.ReportZero
+		check["level"] = string(level)
+	default:
+		return nil, fmt.Errorf("check %q declares neither a threshold nor a deadman check", spec.Name)
+	}
+
+	return check, nil
+}
+
+// activityStatus returns status, where an empty status is active.
+func activityStatus(status paradoxv1alpha1.ActivityStatus) paradoxv1alpha1.ActivityStatus {
+	if status == "" {
+		return paradoxv1alpha1.ActivityStatusActive
+	}
+
+	return status
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CheckReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &paradoxv1alpha1.Check{}, orgField, func(rawObj client.Object) []string {
+		check := rawObj.(*paradoxv1alpha1.Check)
+		if check.Spec.Organization == "" {
+			return nil
+		}
+
+		return []string{check.Spec.Organization}
+	}); err != nil {
+		return err
+	}
+
+	if err := indexLabels(mgr, &paradoxv1alpha1.Check{}, func(rawObj client.Object) []string {
+		return rawObj.(*paradoxv1alpha1.Check).Spec.Labels
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&paradoxv1alpha1.Check{}, builder.WithPredicates(specOrAnnotationChanged())).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Organization{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.CheckList{}, orgField)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Label{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.CheckList{}, labelsField)),
+		).
+		Complete(r)
+}
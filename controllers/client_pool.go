@@ -0,0 +1,211 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	clientPoolOpen = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "paradox_influx_client_pool_open_clients",
+		Help: "Number of Influx clients currently held open by the client pool.",
+	})
+	clientPoolRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "paradox_influx_client_pool_requests_total",
+		Help: "Number of Influx clients requested from the client pool, by whether an open client was reused.",
+	}, []string{"result"})
+	clientPoolClosed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "paradox_influx_client_pool_closed_total",
+		Help: "Number of Influx clients closed by the client pool, by reason.",
+	}, []string{"reason"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(clientPoolOpen, clientPoolRequests, clientPoolClosed)
+}
+
+const (
+	// operatorCredential is the credential source of the operator credential
+	// defined on an Instance itself.
+	operatorCredential = "operator"
+
+	closeReasonCredentialChanged   = "credential_changed"
+	closeReasonInstanceDeleted     = "instance_deleted"
+	closeReasonOrganizationDeleted = "organization_deleted"
+)
+
+// clientKey identifies a pooled client by a hash of the connection configuration
+// of the instance it targets, and a hash of the token it authorizes requests with.
+type clientKey struct {
+	connection string
+	credential string
+}
+
+// pooledClient is an open client along with the credential sources using it.
+type pooledClient struct {
+	client  influxdb.Client
+	sources map[credentialSource]struct{}
+	// leases is the number of callers currently holding the client, which is
+	// only closed once none do.
+	leases int
+	// closeReason is set once the client has been removed from the pool, and
+	// is recorded when the client is closed.
+	closeReason string
+}
+
+// credentialSource identifies where the credential used for an instance was
+// resolved from, such as a Secret, so that the client can be replaced when
+// the credential it holds changes.
+type credentialSource struct {
+	instance types.NamespacedName
+	source   string
+}
+
+// ClientPool shares open Influx clients, and so their HTTP transports, across
+// reconciles and reconcilers. Clients are removed from the pool once no credential
+// source resolves to them, or when the instance they target or the organization
+// holding their token is deleted, and are closed once no caller holds them.
+type ClientPool struct {
+	mu      sync.Mutex
+	clients map[clientKey]*pooledClient
+	sources map[credentialSource]clientKey
+}
+
+// NewClientPool returns an empty client pool.
+func NewClientPool() *ClientPool {
+	return &ClientPool{
+		clients: map[clientKey]*pooledClient{},
+		sources: map[credentialSource]clientKey{},
+	}
+}
+
+// Get returns a client for conn authorized by token, which was resolved from
+// source for the instance identified by instance, along with a function which
+// must be called once the caller no longer uses the client. The client previously
+// returned for the same instance and source is released when the connection or
+// token differ. Clients are owned by the pool and must not be closed by the caller.
+func (p *ClientPool) Get(instance types.NamespacedName, source string, conn *instanceConnection, token string) (influxdb.Client, func()) {
+	sum := sha256.Sum256([]byte(token))
+	key := clientKey{connection: conn.hash, credential: hex.EncodeToString(sum[:])}
+	src := credentialSource{instance: instance, source: source}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if previous, ok := p.sources[src]; ok && previous != key {
+		p.release(src, previous, closeReasonCredentialChanged)
+	}
+
+	pooled, ok := p.clients[key]
+	if ok {
+		clientPoolRequests.WithLabelValues("hit").Inc()
+	} else {
+		clientPoolRequests.WithLabelValues("miss").Inc()
+		clientPoolOpen.Inc()
+
+		pooled = &pooledClient{
+			client:  conn.newClient(token),
+			sources: map[credentialSource]struct{}{},
+		}
+		p.clients[key] = pooled
+	}
+
+	pooled.sources[src] = struct{}{}
+	p.sources[src] = key
+	pooled.leases++
+
+	var once sync.Once
+
+	return pooled.client, func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+
+			pooled.leases--
+			p.closeIdle(pooled)
+		})
+	}
+}
+
+// CloseInstance closes every client in use for the instance identified by instance.
+func (p *ClientPool) CloseInstance(instance types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for src, key := range p.sources {
+		if src.instance == instance {
+			p.release(src, key, closeReasonInstanceDeleted)
+		}
+	}
+}
+
+// CloseOrganization closes every client authorized by a token declared by the
+// organization identified by organization.
+func (p *ClientPool) CloseOrganization(organization types.NamespacedName) {
+	source := organizationCredentialSource(organization)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for src, key := range p.sources {
+		if src.source == source {
+			p.release(src, key, closeReasonOrganizationDeleted)
+		}
+	}
+}
+
+// release removes src from the client identified by key, removing the client
+// from the pool when no other source uses it. The caller must hold the lock.
+func (p *ClientPool) release(src credentialSource, key clientKey, reason string) {
+	delete(p.sources, src)
+
+	pooled, ok := p.clients[key]
+	if !ok {
+		return
+	}
+
+	delete(pooled.sources, src)
+	if len(pooled.sources) > 0 {
+		return
+	}
+
+	delete(p.clients, key)
+	pooled.closeReason = reason
+	p.closeIdle(pooled)
+}
+
+// closeIdle closes pooled once it has been removed from the pool and no caller
+// holds it, so that requests in flight are not interrupted. The caller must hold
+// the lock.
+func (p *ClientPool) closeIdle(pooled *pooledClient) {
+	if pooled.closeReason == "" || pooled.leases > 0 {
+		return
+	}
+
+	pooled.client.Close()
+
+	clientPoolOpen.Dec()
+	clientPoolClosed.WithLabelValues(pooled.closeReason).Inc()
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// testConnection returns the connection configuration of an instance at address,
+// identified by its address alone.
+func testConnection(address string) *instanceConnection {
+	return &instanceConnection{address: address, timeout: defaultInstanceTimeout, hash: address}
+}
+
+func testClientKey(address, token string) clientKey {
+	sum := sha256.Sum256([]byte(token))
+
+	return clientKey{connection: address, credential: hex.EncodeToString(sum[:])}
+}
+
+// get returns a client from p which is released immediately, as the tests
+// are concerned with the clients held by the pool rather than by callers.
+func get(p *ClientPool, instance types.NamespacedName, source string, conn *instanceConnection, token string) influxdb.Client {
+	client, release := p.Get(instance, source, conn, token)
+	release()
+
+	return client
+}
+
+func TestClientPoolGet(t *testing.T) {
+	primary := types.NamespacedName{Namespace: "influx", Name: "primary"}
+	secondary := types.NamespacedName{Namespace: "influx", Name: "secondary"}
+
+	p := NewClientPool()
+
+	operator := get(p, primary, operatorCredential, testConnection("http://primary:8086"), "admin")
+	if get(p, primary, operatorCredential, testConnection("http://primary:8086"), "admin") != operator {
+		t.Error("client not reused for the same source, connection and token")
+	}
+
+	// sources resolving to the same address and token share a client
+	shared := get(p, primary, "secret:default/credentials", testConnection("http://primary:8086"), "admin")
+	if shared != operator {
+		t.Error("client not shared between sources with the same connection and token")
+	}
+
+	if get(p, primary, "secret:default/other", testConnection("http://primary:8086"), "other") == operator {
+		t.Error("client shared between different tokens")
+	}
+
+	if get(p, secondary, operatorCredential, testConnection("http://secondary:8086"), "admin") == operator {
+		t.Error("client shared between different connections")
+	}
+
+	if got, want := len(p.clients), 3; got != want {
+		t.Fatalf("pool holds %d clients, want %d", got, want)
+	}
+
+	// a changed credential releases the previous client, which remains open
+	// while the other source still uses it
+	rotated := get(p, primary, operatorCredential, testConnection("http://primary:8086"), "rotated")
+	if rotated == operator {
+		t.Error("client reused after the token changed")
+	}
+
+	if _, ok := p.clients[testClientKey("http://primary:8086", "admin")]; !ok {
+		t.Error("client closed while still in use by another source")
+	}
+
+	// once the last source moves on the previous client is closed
+	get(p, primary, "secret:default/credentials", testConnection("http://primary:8086"), "rotated")
+	if _, ok := p.clients[testClientKey("http://primary:8086", "admin")]; ok {
+		t.Error("client retained once no source uses it")
+	}
+
+	if got, want := len(p.clients), 3; got != want {
+		t.Errorf("pool holds %d clients, want %d", got, want)
+	}
+}
+
+func TestClientPoolCloseInstance(t *testing.T) {
+	primary := types.NamespacedName{Namespace: "influx", Name: "primary"}
+	secondary := types.NamespacedName{Namespace: "influx", Name: "secondary"}
+
+	p := NewClientPool()
+	get(p, primary, operatorCredential, testConnection("http://primary:8086"), "admin")
+	get(p, primary, "secret:default/credentials", testConnection("http://primary:8086"), "user")
+	get(p, secondary, operatorCredential, testConnection("http://secondary:8086"), "admin")
+
+	p.CloseInstance(primary)
+
+	if got, want := len(p.clients), 1; got != want {
+		t.Errorf("pool holds %d clients, want %d", got, want)
+	}
+
+	for src := range p.sources {
+		if src.instance == primary {
+			t.Errorf("source %v retained after its instance was closed", src)
+		}
+	}
+
+	if _, ok := p.sources[credentialSource{secondary, operatorCredential}]; !ok {
+		t.Error("source of another instance released")
+	}
+}
+
+func TestClientPoolCloseOrganization(t *testing.T) {
+	primary := types.NamespacedName{Namespace: "influx", Name: "primary"}
+	secondary := types.NamespacedName{Namespace: "influx", Name: "secondary"}
+	macro := types.NamespacedName{Namespace: "default", Name: "macro"}
+	other := types.NamespacedName{Namespace: "default", Name: "other"}
+
+	p := NewClientPool()
+	get(p, primary, organizationCredentialSource(macro), testConnection("http://primary:8086"), "macro")
+	get(p, secondary, organizationCredentialSource(macro), testConnection("http://secondary:8086"), "macro")
+	get(p, primary, organizationCredentialSource(other), testConnection("http://primary:8086"), "other")
+
+	p.CloseOrganization(macro)
+
+	if got, want := len(p.clients), 1; got != want {
+		t.Errorf("pool holds %d clients, want %d", got, want)
+	}
+
+	if _, ok := p.sources[credentialSource{primary, organizationCredentialSource(other)}]; !ok {
+		t.Error("source of another organization released")
+	}
+}
+
+func TestClientPoolLeases(t *testing.T) {
+	primary := types.NamespacedName{Namespace: "influx", Name: "primary"}
+	closed := clientPoolClosed.WithLabelValues(closeReasonCredentialChanged)
+
+	p := NewClientPool()
+	_, release := p.Get(primary, operatorCredential, testConnection("http://primary:8086"), "admin")
+	_, second := p.Get(primary, operatorCredential, testConnection("http://primary:8086"), "admin")
+	second()
+	second()
+
+	before := testutil.ToFloat64(closed)
+
+	// the rotated credential replaces the client in the pool, but the client
+	// remains open while a caller holds it
+	get(p, primary, operatorCredential, testConnection("http://primary:8086"), "rotated")
+	if _, ok := p.clients[testClientKey("http://primary:8086", "admin")]; ok {
+		t.Error("client retained in the pool once no source uses it")
+	}
+
+	if got := testutil.ToFloat64(closed) - before; got != 0 {
+		t.Fatalf("%v clients closed while held by a caller", got)
+	}
+
+	release()
+
+	if got := testutil.ToFloat64(closed) - before; got != 1 {
+		t.Errorf("%v clients closed once released, want 1", got)
+	}
+}
@@ -0,0 +1,377 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"sort"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/http"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+var ErrInvalidDashboardExport = errors.New("not a dashboard exported from the Influx UI")
+
+// dashboardCell is a cell of a dashboard along with the properties of its view.
+type dashboardCell struct {
+	Name       string
+	X, Y, W, H int32
+	Properties influxObject
+}
+
+// position returns the grid position of the cell.
+func (c dashboardCell) position() [4]int32 {
+	return [4]int32{c.X, c.Y, c.W, c.H}
+}
+
+// dashboardExport is the JSON document produced by exporting a dashboard from
+// the Influx UI. Cells and their views are included alongside the dashboard
+// and related to it by identifier.
+type dashboardExport struct {
+	Content struct {
+		Data struct {
+			Type          string `json:"type"`
+			Relationships struct {
+				Cell struct {
+					Data []dashboardExportRef `json:"data"`
+				} `json:"cell"`
+			} `json:"relationships"`
+		} `json:"data"`
+		Included []struct {
+			dashboardExportRef
+			Attributes    json.RawMessage `json:"attributes"`
+			Relationships struct {
+				View struct {
+					Data dashboardExportRef `json:"data"`
+				} `json:"view"`
+			} `json:"relationships"`
+		} `json:"included"`
+	} `json:"content"`
+}
+
+// dashboardExportRef identifies a resource included within a dashboard export.
+type dashboardExportRef struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// parseDashboardExport returns the cells of the dashboard exported as data.
+func parseDashboardExport(data []byte) ([]dashboardCell, error) {
+	var export dashboardExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDashboardExport, err)
+	}
+
+	if export.Content.Data.Type != "dashboard" {
+		return nil, ErrInvalidDashboardExport
+	}
+
+	included := make(map[dashboardExportRef]int, len(export.Content.Included))
+	for i, resource := range export.Content.Included {
+		included[resource.dashboardExportRef] = i
+	}
+
+	cells := make([]dashboardCell, 0, len(export.Content.Data.Relationships.Cell.Data))
+	for _, ref := range export.Content.Data.Relationships.Cell.Data {
+		i, ok := included[ref]
+		if !ok {
+			return nil, fmt.Errorf("%w: cell %s is not included", ErrInvalidDashboardExport, ref.ID)
+		}
+
+		cell := export.Content.Included[i]
+
+		var position struct {
+			X, Y, W, H int32
+		}
+		if err := json.Unmarshal(cell.Attributes, &position); err != nil {
+			return nil, fmt.Errorf("%w: cell %s: %s", ErrInvalidDashboardExport, ref.ID, err)
+		}
+
+		j, ok := included[cell.Relationships.View.Data]
+		if !ok {
+			return nil, fmt.Errorf("%w: view of cell %s is not included", ErrInvalidDashboardExport, ref.ID)
+		}
+
+		var view struct {
+			Name       string       `json:"name"`
+			Properties influxObject `json:"properties"`
+		}
+		if err := json.Unmarshal(export.Content.Included[j].Attributes, &view); err != nil {
+			return nil, fmt.Errorf("%w: view of cell %s: %s", ErrInvalidDashboardExport, ref.ID, err)
+		}
+
+		cells = append(cells, dashboardCell{
+			Name:       view.Name,
+			X:          position.X,
+			Y:          position.Y,
+			W:          position.W,
+			H:          position.H,
+			Properties: view.Properties,
+		})
+	}
+
+	return cells, nil
+}
+
+// inlineDashboardCell returns the cell declared by cell, with the view
+// properties of its type.
+func inlineDashboardCell(cell paradoxv1alpha1.DashboardCell) dashboardCell {
+	cellType := cell.Type
+	if cellType == "" {
+		cellType = paradoxv1alpha1.DashboardCellTypeXY
+	}
+
+	queries := make([]influxObject, len(cell.Queries))
+	for i, query := range cell.Queries {
+		queries[i] = influxObject{
+			"text":     query,
+			"editMode": "advanced",
+			"name":     "",
+			"builderConfig": influxObject{
+				"buckets":         []string{},
+				"tags":            []influxObject{},
+				"functions":       []influxObject{},
+				"aggregateWindow": influxObject{"period": "auto", "fillValues": false},
+			},
+		}
+	}
+
+	decimalPlaces := influxObject{"isEnforced": false, "digits": 2}
+
+	properties := influxObject{
+		"type":              string(cellType),
+		"shape":             "chronograf-v2",
+		"note":              cell.Note,
+		"showNoteWhenEmpty": false,
+	}
+
+	switch cellType {
+	case paradoxv1alpha1.DashboardCellTypeXY:
+		axis := influxObject{"bounds": []string{"", ""}, "label": "", "prefix": "", "suffix": "", "base": "10", "scale": "linear"}
+
+		properties["queries"] = queries
+		properties["colors"] = []influxObject{}
+		properties["axes"] = influxObject{"x": axis, "y": axis}
+		properties["geom"] = "line"
+		properties["position"] = "overlaid"
+		properties["xColumn"] = "_time"
+		properties["yColumn"] = "_value"
+	case paradoxv1alpha1.DashboardCellTypeSingleStat:
+		properties["queries"] = queries
+		properties["colors"] = []influxObject{}
+		properties["prefix"] = ""
+		properties["suffix"] = ""
+		properties["tickPrefix"] = ""
+		properties["tickSuffix"] = ""
+		properties["decimalPlaces"] = decimalPlaces
+	case paradoxv1alpha1.DashboardCellTypeTable:
+		properties["queries"] = queries
+		properties["colors"] = []influxObject{}
+		properties["tableOptions"] = influxObject{"verticalTimeAxis": true, "fixFirstColumn": false}
+		properties["fieldOptions"] = []influxObject{}
+		properties["timeFormat"] = "YYYY-MM-DD HH:mm:ss"
+		properties["decimalPlaces"] = decimalPlaces
+	}
+
+	return dashboardCell{
+		Name:       cell.Name,
+		X:          cell.X,
+		Y:          cell.Y,
+		W:          cell.W,
+		H:          cell.H,
+		Properties: properties,
+	}
+}
+
+// syncDashboard creates the dashboard named name within the organization identified
+// by orgID, or corrects the existing dashboard where it has drifted from the desired
+// description and cells. Existing cells are matched with desired cells by name, so
+// that cells keep their identifiers while their position or view is corrected.
+// The identifier of the dashboard is returned along with a description of each
+// difference which was corrected.
+func syncDashboard(
+	ctx context.Context,
+	service http.Service,
+	previous *paradoxv1alpha1.InfluxID,
+	orgID, name, description string,
+	cells []dashboardCell,
+) (*paradoxv1alpha1.InfluxID, []string, error) {
+	existing, err := dashboardsResource.find(ctx, service, previous, name, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changes []string
+
+	created := existing == nil
+	if created {
+		if err := doInfluxRequest(ctx, service, nethttp.MethodPost, dashboardsResource.url(service, "", nil), influxObject{
+			"orgID":       orgID,
+			"name":        name,
+			"description": description,
+		}, &existing); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		if current := fromAny[string](existing["name"]); current != name {
+			changes = append(changes, fmt.Sprintf("name %q -> %q", current, name))
+		}
+
+		if current := fromAny[string](existing["description"]); current != description {
+			changes = append(changes, fmt.Sprintf("description %q -> %q", current, description))
+		}
+	}
+
+	id := influxObjectID(existing)
+	if id == nil {
+		return nil, nil, fmt.Errorf("dashboards %q: %w", name, ErrInfluxUnexpectedResponse)
+	}
+
+	dashboardURL := dashboardsResource.url(service, string(*id), nil)
+
+	if len(changes) > 0 {
+		if err := doInfluxRequest(ctx, service, nethttp.MethodPatch, dashboardURL, influxObject{
+			"name":        name,
+			"description": description,
+		}, nil); err != nil {
+			return id, nil, err
+		}
+	}
+
+	var dashboard struct {
+		Cells []influxObject `json:"cells"`
+	}
+	if err := doInfluxRequest(ctx, service, nethttp.MethodGet, dashboardsResource.url(service, string(*id), url.Values{
+		"include": []string{"properties"},
+	}), nil, &dashboard); err != nil {
+		return id, nil, err
+	}
+
+	existingCells := map[string][]influxObject{}
+	for _, cell := range dashboard.Cells {
+		cellName := fromAny[string](cell["name"])
+		existingCells[cellName] = append(existingCells[cellName], cell)
+	}
+
+	for _, cell := range cells {
+		properties, err := toInfluxObject(cell.Properties)
+		if err != nil {
+			return id, changes, err
+		}
+
+		view := influxObject{"name": cell.Name, "properties": properties}
+
+		candidates := existingCells[cell.Name]
+		if len(candidates) == 0 {
+			var created influxObject
+			if err := doInfluxRequest(ctx, service, nethttp.MethodPost, dashboardURL+"/cells", influxObject{
+				"name": cell.Name,
+				"x":    cell.X,
+				"y":    cell.Y,
+				"w":    cell.W,
+				"h":    cell.H,
+			}, &created); err != nil {
+				return id, changes, err
+			}
+
+			cellID := influxObjectID(created)
+			if cellID == nil {
+				return id, changes, fmt.Errorf("cell %q: %w", cell.Name, ErrInfluxUnexpectedResponse)
+			}
+
+			if err := doInfluxRequest(ctx, service, nethttp.MethodPatch, dashboardURL+"/cells/"+url.PathEscape(string(*cellID))+"/view", view, nil); err != nil {
+				return id, changes, err
+			}
+
+			changes = append(changes, fmt.Sprintf("cell %q added", cell.Name))
+
+			continue
+		}
+
+		current := candidates[0]
+		existingCells[cell.Name] = candidates[1:]
+
+		cellURL := dashboardURL + "/cells/" + url.PathEscape(fromAny[string](current["id"]))
+
+		if cellPosition(current) != cell.position() {
+			if err := doInfluxRequest(ctx, service, nethttp.MethodPatch, cellURL, influxObject{
+				"x": cell.X,
+				"y": cell.Y,
+				"w": cell.W,
+				"h": cell.H,
+			}, nil); err != nil {
+				return id, changes, err
+			}
+
+			changes = append(changes, fmt.Sprintf("cell %q position", cell.Name))
+		}
+
+		if drift := objectDrift("", current["properties"], properties, nil); len(drift) > 0 {
+			if err := doInfluxRequest(ctx, service, nethttp.MethodPatch, cellURL+"/view", view, nil); err != nil {
+				return id, changes, err
+			}
+
+			changes = append(changes, fmt.Sprintf("cell %q view", cell.Name))
+		}
+	}
+
+	names := make([]string, 0, len(existingCells))
+	for cellName := range existingCells {
+		names = append(names, cellName)
+	}
+
+	sort.Strings(names)
+
+	for _, cellName := range names {
+		for _, cell := range existingCells[cellName] {
+			if err := doInfluxRequest(ctx, service, nethttp.MethodDelete, dashboardURL+"/cells/"+url.PathEscape(fromAny[string](cell["id"])), nil, nil); err != nil && !isInfluxNotFound(err) {
+				return id, changes, err
+			}
+
+			changes = append(changes, fmt.Sprintf("cell %q removed", cellName))
+		}
+	}
+
+	// the cells of a newly created dashboard are not drift
+	if created {
+		return id, nil, nil
+	}
+
+	return id, changes, nil
+}
+
+// cellPosition returns the grid position of the cell decoded from obj.
+func cellPosition(obj influxObject) (position [4]int32) {
+	for i, key := range []string{"x", "y", "w", "h"} {
+		position[i] = int32(fromAny[float64](obj[key]))
+	}
+
+	return position
+}
+
+// fromAny returns v as T, or the zero value of T when v is not a T.
+func fromAny[T any](v interface{}) T {
+	value, _ := v.(T)
+
+	return value
+}
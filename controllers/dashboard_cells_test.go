@@ -0,0 +1,109 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseDashboardExport(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "dashboard_export.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cells, err := parseDashboardExport(data)
+	if err != nil {
+		t.Fatalf("parseDashboardExport() error = %v", err)
+	}
+
+	want := []struct {
+		name       string
+		position   [4]int32
+		viewType   string
+		queryCount int
+	}{
+		{name: "System Uptime", position: [4]int32{0, 0, 3, 1}, viewType: "single-stat", queryCount: 1},
+		{name: "CPU Usage", position: [4]int32{3, 0, 9, 3}, viewType: "xy", queryCount: 1},
+	}
+
+	if len(cells) != len(want) {
+		t.Fatalf("parseDashboardExport() returned %d cells, want %d", len(cells), len(want))
+	}
+
+	for i, cell := range cells {
+		if cell.Name != want[i].name {
+			t.Errorf("cell %d name = %q, want %q", i, cell.Name, want[i].name)
+		}
+
+		if cell.position() != want[i].position {
+			t.Errorf("cell %d position = %v, want %v", i, cell.position(), want[i].position)
+		}
+
+		if cell.Properties["type"] != want[i].viewType {
+			t.Errorf("cell %d view type = %v, want %q", i, cell.Properties["type"], want[i].viewType)
+		}
+
+		if queries, _ := cell.Properties["queries"].([]interface{}); len(queries) != want[i].queryCount {
+			t.Errorf("cell %d has %d queries, want %d", i, len(queries), want[i].queryCount)
+		}
+	}
+}
+
+func TestParseDashboardExportInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "not json",
+			data: `apiVersion: influxdata.com/v2alpha1`,
+		},
+		{
+			name: "not a dashboard",
+			data: `{"meta": {"type": "variable"}, "content": {"data": {"type": "variable", "attributes": {"name": "host"}}}}`,
+		},
+		{
+			name: "cell not included",
+			data: `{"content": {"data": {"type": "dashboard", "relationships": {"cell": {"data": [{"type": "cell", "id": "1"}]}}}, "included": []}}`,
+		},
+		{
+			name: "view not included",
+			data: `{"content": {"data": {"type": "dashboard", "relationships": {"cell": {"data": [{"type": "cell", "id": "1"}]}}}, "included": [
+				{"type": "cell", "id": "1", "attributes": {"x": 0, "y": 0, "w": 4, "h": 4}, "relationships": {"view": {"data": {"type": "view", "id": "1"}}}}
+			]}}`,
+		},
+		{
+			name: "invalid position",
+			data: `{"content": {"data": {"type": "dashboard", "relationships": {"cell": {"data": [{"type": "cell", "id": "1"}]}}}, "included": [
+				{"type": "cell", "id": "1", "attributes": {"x": "left"}, "relationships": {"view": {"data": {"type": "view", "id": "1"}}}},
+				{"type": "view", "id": "1", "attributes": {"name": "CPU", "properties": {"type": "xy"}}}
+			]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseDashboardExport([]byte(tt.data)); !errors.Is(err, ErrInvalidDashboardExport) {
+				t.Errorf("parseDashboardExport() error = %v, want %v", err, ErrInvalidDashboardExport)
+			}
+		})
+	}
+}
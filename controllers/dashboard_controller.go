@@ -0,0 +1,286 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// dashboardConfigMapField indexes dashboards by the ConfigMap holding their export.
+const dashboardConfigMapField = ".spec.configMapRef"
+
+// DashboardReconciler reconciles a Dashboard object
+type DashboardReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Clients is the pool of Influx clients shared by every reconciler.
+	Clients *ClientPool
+	// ResyncInterval is the interval at which each dashboard is reconciled
+	// against its target instances, unless overridden by annotation.
+	ResyncInterval time.Duration
+}
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=dashboards,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=dashboards/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=dashboards/finalizers,verbs=update
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations/status,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=labels,verbs=get;list;watch
+
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// Dashboards which already exist are compared against the spec and any drift
+// in their description, or in the position and view of their cells, is corrected.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *DashboardReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var dashboard paradoxv1alpha1.Dashboard
+	if err := r.Get(ctx, req.NamespacedName, &dashboard); err != nil {
+		log.Error(err, "unable to fetch dashboard")
+
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	log = log.WithValues("dashboard", dashboard)
+
+	if !dashboard.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := finalize(ctx, r.Client, &dashboard, dashboard.Spec.DeletionPolicy, func() error {
+			return r.deleteInstanceDashboards(ctx, &dashboard)
+		}); err != nil {
+			log.Error(err, "failed to finalize dashboard")
+
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if err := syncFinalizer(ctx, r.Client, &dashboard, dashboard.Spec.DeletionPolicy); err != nil {
+		log.Error(err, "failed to update finalizers")
+
+		return ctrl.Result{}, err
+	}
+
+	var organization paradoxv1alpha1.Organization
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: req.NamespacedName.Namespace,
+		Name:      dashboard.Spec.Organization,
+	}, &organization); err != nil {
+		log.Error(err, "unable to fetch organization")
+
+		return ctrl.Result{}, client.IgnoreNotFound(r.updateStatus(ctx, &dashboard, dashboard.Status.Instances, dashboard.Status.LabelMappings, fmt.Errorf("organization %q: %w", dashboard.Spec.Organization, err)))
+	}
+
+	cells, err := r.resolveCells(ctx, &dashboard)
+	if err != nil {
+		log.Error(err, "unable to resolve dashboard cells")
+
+		return ctrl.Result{}, r.updateStatus(ctx, &dashboard, dashboard.Status.Instances, dashboard.Status.LabelMappings, err)
+	}
+
+	labels, err := fetchLabels(ctx, r.Client, req.NamespacedName.Namespace, dashboard.Spec.Organization, dashboard.Spec.Labels)
+	if err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, &dashboard, dashboard.Status.Instances, dashboard.Status.LabelMappings, err)
+	}
+
+	var (
+		mu       sync.Mutex
+		drift    []string
+		mappings = newLabelMappings(dashboard.Status.LabelMappings)
+	)
+
+	instances, err := reconcileInstances(ctx, r.Client, r.Clients, &organization, dashboard.Status.Instances, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) (*paradoxv1alpha1.InfluxID, error) {
+		namespace, name := instance.ObjectMeta.Namespace, instance.ObjectMeta.Name
+
+		orgID, err := organizationID(&organization, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		id, changes, err := syncDashboard(ctx, client.HTTPService(), dashboard.Status.Instances[namespace][name].ID, orgID, dashboard.Spec.Name, dashboard.Spec.Description, cells)
+		if err == nil {
+			var labelDrift []string
+			labelDrift, err = labels.syncLabelMappings(ctx, client.HTTPService(), instance, dashboardsResource.path, *id, mappings)
+			changes = append(changes, labelDrift...)
+		}
+
+		if len(changes) > 0 {
+			message := fmt.Sprintf("corrected drift in instance %s/%s: %s", namespace, name, strings.Join(changes, ", "))
+			r.Recorder.Event(&dashboard, corev1.EventTypeNormal, "DriftCorrected", message)
+
+			mu.Lock()
+			drift = append(drift, message)
+			mu.Unlock()
+		}
+
+		return id, err
+	})
+	if err != nil {
+		log.Error(err, "error while configuring instances")
+	}
+
+	setDriftCondition(&dashboard.Status.Conditions, dashboard.Generation, drift)
+
+	return resyncResult(ctx, &dashboard, r.ResyncInterval), r.updateStatus(ctx, &dashboard, instances, mappings.assigned, err)
+}
+
+// updateStatus records instances and label mappings along with the conditions
+// derived from reconcileErr in the status of dashboard. The reconcile error is returned
+// unless the status update itself fails.
+func (r *DashboardReconciler) updateStatus(ctx context.Context, dashboard *paradoxv1alpha1.Dashboard, instances paradoxv1alpha1.Instances, mappings paradoxv1alpha1.InstanceLabels, reconcileErr error) error {
+	if instances == nil {
+		instances = paradoxv1alpha1.Instances{}
+	}
+
+	// label mappings are only kept for instances which are still recorded
+	mappings.Retain(instances)
+
+	dashboard.Status.ObservedGeneration = dashboard.Generation
+	dashboard.Status.Instances = instances
+	dashboard.Status.LabelMappings = mappings
+	setConditions(&dashboard.Status.Conditions, dashboard.Generation, instances, reconcileErr)
+
+	if err := r.Status().Update(ctx, dashboard); err != nil {
+		log.FromContext(ctx).Error(err, "failed to update status")
+
+		return err
+	}
+
+	return reconcileErr
+}
+
+// resolveCells returns the cells of dashboard, either declared inline or
+// read from the dashboard export held by the referenced ConfigMap.
+func (r *DashboardReconciler) resolveCells(ctx context.Context, dashboard *paradoxv1alpha1.Dashboard) ([]dashboardCell, error) {
+	ref := dashboard.Spec.ConfigMapRef
+	if ref == nil {
+		cells := make([]dashboardCell, len(dashboard.Spec.Cells))
+		for i, cell := range dashboard.Spec.Cells {
+			cells[i] = inlineDashboardCell(cell)
+		}
+
+		return cells, nil
+	}
+
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &configMap); err != nil {
+		return nil, fmt.Errorf("dashboard config map: %w", err)
+	}
+
+	export, ok := configMap.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("dashboard config map '%s/%s' has no key %s", ref.Namespace, ref.Name, ref.Key)
+	}
+
+	cells, err := parseDashboardExport([]byte(export))
+	if err != nil {
+		return nil, fmt.Errorf("dashboard config map '%s/%s' key %s: %w", ref.Namespace, ref.Name, ref.Key, err)
+	}
+
+	return cells, nil
+}
+
+// deleteInstanceDashboards removes the dashboard from every target instance in
+// which it has previously been recorded.
+func (r *DashboardReconciler) deleteInstanceDashboards(ctx context.Context, dashboard *paradoxv1alpha1.Dashboard) error {
+	deletion := instanceDeletion{
+		Client:    r.Client,
+		Clients:   r.Clients,
+		Recorder:  r.Recorder,
+		Object:    dashboard,
+		Noun:      "dashboard",
+		Instances: dashboard.Status.Instances,
+	}
+
+	return deletion.deleteWithin(ctx, dashboard.Spec.Organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client, id paradoxv1alpha1.InfluxID) error {
+		return dashboardsResource.delete(ctx, client.HTTPService(), id)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DashboardReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.Dashboard{}, orgField, func(rawObj client.Object) []string {
+		dashboard := rawObj.(*paradoxv1alpha1.Dashboard)
+		if dashboard.Spec.Organization == "" {
+			return nil
+		}
+
+		return []string{dashboard.Spec.Organization}
+	}); err != nil {
+		return err
+	}
+
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.Dashboard{}, dashboardConfigMapField, func(rawObj client.Object) []string {
+		dashboard := rawObj.(*paradoxv1alpha1.Dashboard)
+		if ref := dashboard.Spec.ConfigMapRef; ref != nil {
+			return []string{types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}.String()}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := indexLabels(mgr, &paradoxv1alpha1.Dashboard{}, func(rawObj client.Object) []string {
+		return rawObj.(*paradoxv1alpha1.Dashboard).Spec.Labels
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&paradoxv1alpha1.Dashboard{}, builder.WithPredicates(specOrAnnotationChanged())).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Organization{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.DashboardList{}, orgField)),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(findForReference(r.Client, &paradoxv1alpha1.DashboardList{}, dashboardConfigMapField)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Label{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.DashboardList{}, labelsField)),
+		).
+		Complete(r)
+}
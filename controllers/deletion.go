@@ -0,0 +1,141 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// instanceDeletion removes a resource from the target instances in which it
+// has been recorded, warning of any which can no longer be reached.
+type instanceDeletion struct {
+	client.Client
+	Clients  *ClientPool
+	Recorder record.EventRecorder
+
+	// Object is the resource being deleted, against which events are recorded.
+	Object client.Object
+	// Noun describes the kind of the resource, e.g. "bucket".
+	Noun string
+	// Instances are the instances in which the resource has been recorded.
+	Instances paradoxv1alpha1.Instances
+}
+
+// deleteWithin calls fn for every instance in which the resource has been recorded
+// with the identifier recorded for it, where the instances are reached by way of the
+// organization named name within the namespace of the resource. When the organization
+// no longer exists the resource is orphaned in every instance.
+func (d instanceDeletion) deleteWithin(ctx context.Context, name string, fn func(instance *paradoxv1alpha1.Instance, client influxdb.Client, id paradoxv1alpha1.InfluxID) error) error {
+	var organization paradoxv1alpha1.Organization
+	if err := d.Get(ctx, types.NamespacedName{
+		Namespace: d.Object.GetNamespace(),
+		Name:      name,
+	}, &organization); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+
+		d.orphaned(func(types.NamespacedName) bool { return true }, fmt.Sprintf("organization %q no longer exists", name))
+
+		return nil
+	}
+
+	return d.delete(ctx, &organization, fn)
+}
+
+// delete calls fn for every target instance of organization in which the resource
+// has been recorded, with the identifier recorded for it. The resource is orphaned
+// in instances which no longer exist, or are no longer targeted by the organization,
+// and a warning is recorded for each. Any other failure, such as a missing credential,
+// is returned so that the resource is retained until it can be removed.
+func (d instanceDeletion) delete(ctx context.Context, organization *paradoxv1alpha1.Organization, fn func(instance *paradoxv1alpha1.Instance, client influxdb.Client, id paradoxv1alpha1.InfluxID) error) error {
+	var (
+		mu      sync.Mutex
+		visited = map[types.NamespacedName]struct{}{}
+	)
+
+	err := forEachInstanceClient(ctx, d.Client, d.Clients, organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) error {
+		key := types.NamespacedName{Namespace: instance.ObjectMeta.Namespace, Name: instance.ObjectMeta.Name}
+
+		mu.Lock()
+		visited[key] = struct{}{}
+		mu.Unlock()
+
+		id := d.Instances[key.Namespace][key.Name].ID
+		if id == nil {
+			return nil
+		}
+
+		return fn(instance, client, *id)
+	})
+
+	missing := map[types.NamespacedName]struct{}{}
+	err = utilerrors.FilterOut(err, func(err error) bool {
+		var ierr *instanceError
+		if !errors.As(err, &ierr) || !errors.Is(ierr.Err, ErrInstanceNotFound) {
+			return false
+		}
+
+		missing[types.NamespacedName{Namespace: ierr.Namespace, Name: ierr.Name}] = struct{}{}
+
+		return true
+	})
+
+	if err != nil {
+		return err
+	}
+
+	d.orphaned(func(key types.NamespacedName) bool {
+		_, ok := missing[key]
+		return ok
+	}, ErrInstanceNotFound.Error())
+
+	d.orphaned(func(key types.NamespacedName) bool {
+		_, ok := visited[key]
+		_, gone := missing[key]
+		return !ok && !gone
+	}, fmt.Sprintf("instance is no longer targeted by organization %q", organization.ObjectMeta.Name))
+
+	return nil
+}
+
+// orphaned records a warning for every instance matched by match in which the
+// resource has been recorded, as it is left behind for reason.
+func (d instanceDeletion) orphaned(match func(types.NamespacedName) bool, reason string) {
+	for namespace, instances := range d.Instances {
+		for name, resource := range instances {
+			if resource.ID == nil || !match(types.NamespacedName{Namespace: namespace, Name: name}) {
+				continue
+			}
+
+			d.Recorder.Eventf(d.Object, corev1.EventTypeWarning, "Orphaned",
+				"%s %s left in instance %s/%s: %s", d.Noun, *resource.ID, namespace, name, reason)
+		}
+	}
+}
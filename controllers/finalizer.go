@@ -0,0 +1,67 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+const (
+	finalizerName = "paradox.macro.re/finalizer"
+)
+
+// syncFinalizer ensures the paradox finalizer is registered on obj, unless the
+// deletion policy is Orphan, in which case any existing finalizer is removed.
+func syncFinalizer(ctx context.Context, c client.Client, obj client.Object, policy paradoxv1alpha1.DeletionPolicy) error {
+	hasFinalizer := controllerutil.ContainsFinalizer(obj, finalizerName)
+
+	switch {
+	case policy == paradoxv1alpha1.DeletionPolicyOrphan && hasFinalizer:
+		controllerutil.RemoveFinalizer(obj, finalizerName)
+	case policy != paradoxv1alpha1.DeletionPolicyOrphan && !hasFinalizer:
+		controllerutil.AddFinalizer(obj, finalizerName)
+	default:
+		return nil
+	}
+
+	return c.Update(ctx, obj)
+}
+
+// finalize is called for objects which are being deleted.
+// When the deletion policy requires it, fn is called to remove the associated
+// resources from the target instances. Once fn succeeds the paradox
+// finalizer is removed, releasing obj to be deleted.
+func finalize(ctx context.Context, c client.Client, obj client.Object, policy paradoxv1alpha1.DeletionPolicy, fn func() error) error {
+	if !controllerutil.ContainsFinalizer(obj, finalizerName) {
+		return nil
+	}
+
+	if policy.DeletesInstanceResources() {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(obj, finalizerName)
+
+	return c.Update(ctx, obj)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/http"
+)
+
+// doInfluxRequest sends body, when not nil, to url and decodes the response
+// into result, when not nil. It is used for the parts of the Influx API which
+// are not covered, or not usably covered, by the Influx client.
+func doInfluxRequest(ctx context.Context, service http.Service, method, url string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := nethttp.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+
+	if herr := service.DoHTTPRequest(req, func(req *nethttp.Request) {
+		req.Header.Set("Content-Type", "application/json")
+	}, func(resp *nethttp.Response) error {
+		defer resp.Body.Close()
+
+		if result == nil {
+			return nil
+		}
+
+		return json.NewDecoder(resp.Body).Decode(result)
+	}); herr != nil {
+		return herr
+	}
+
+	return nil
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/http"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// influxPageSize is the number of resources requested per page when listing.
+const influxPageSize = 100
+
+// influxObject is the JSON representation of an Influx resource.
+type influxObject = map[string]interface{}
+
+// influxResource is a collection of the Influx API which is not usably covered
+// by the Influx client, such as checks and dashboard views, whose generated
+// models cannot represent their polymorphic types. Resources are managed as
+// plain JSON objects instead.
+type influxResource struct {
+	// path is the path of the collection relative to the API root.
+	path string
+	// listKey is the field of a list response which holds the resources.
+	listKey string
+}
+
+var (
+	checksResource                = influxResource{path: "checks", listKey: "checks"}
+	dashboardsResource            = influxResource{path: "dashboards", listKey: "dashboards"}
+	notificationEndpointsResource = influxResource{path: "notificationEndpoints", listKey: "notificationEndpoints"}
+	notificationRulesResource     = influxResource{path: "notificationRules", listKey: "notificationRules"}
+)
+
+// url returns the URL of the collection, or of the resource identified by id when not empty.
+func (res influxResource) url(service http.Service, id string, query url.Values) string {
+	path := service.ServerAPIURL() + res.path
+	if id != "" {
+		path += "/" + url.PathEscape(id)
+	}
+
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	return path
+}
+
+// find returns the resource previously recorded as id, or else the resource named
+// name within the organization identified by orgID, or nil when neither exists.
+func (res influxResource) find(ctx context.Context, service http.Service, id *paradoxv1alpha1.InfluxID, name, orgID string) (influxObject, error) {
+	if id != nil {
+		var obj influxObject
+		err := doInfluxRequest(ctx, service, nethttp.MethodGet, res.url(service, string(*id), nil), nil, &obj)
+		if err == nil {
+			return obj, nil
+		}
+
+		if !isInfluxNotFound(err) {
+			return nil, err
+		}
+	}
+
+	for offset := 0; ; offset += influxPageSize {
+		var list map[string][]influxObject
+		if err := doInfluxRequest(ctx, service, nethttp.MethodGet, res.url(service, "", url.Values{
+			"orgID":  []string{orgID},
+			"limit":  []string{strconv.Itoa(influxPageSize)},
+			"offset": []string{strconv.Itoa(offset)},
+		}), nil, &list); err != nil {
+			return nil, fmt.Errorf("listing %s: %w", res.path, err)
+		}
+
+		for _, obj := range list[res.listKey] {
+			if obj["name"] == name {
+				return obj, nil
+			}
+		}
+
+		if len(list[res.listKey]) < influxPageSize {
+			return nil, nil
+		}
+	}
+}
+
+// sync creates desired within the organization identified by orgID, or replaces
+// the existing resource when it has drifted from desired, or when force is true.
+// Fields named by ignore, such as secrets which Influx does not return, are
+// excluded from the comparison. The identifier of the resource is returned
+// along with a description of each difference which was corrected.
+func (res influxResource) sync(
+	ctx context.Context,
+	service http.Service,
+	previous *paradoxv1alpha1.InfluxID,
+	orgID string,
+	desired influxObject,
+	force bool,
+	ignore ...string,
+) (*paradoxv1alpha1.InfluxID, []string, error) {
+	// desired is normalized into its JSON representation, so that it can be
+	// compared with the existing resource, without modifying the caller's copy
+	body, err := toInfluxObject(desired)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body["orgID"] = orgID
+
+	name, _ := body["name"].(string)
+
+	existing, err := res.find(ctx, service, previous, name, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if existing == nil {
+		var created influxObject
+		if err := doInfluxRequest(ctx, service, nethttp.MethodPost, res.url(service, "", nil), body, &created); err != nil {
+			return nil, nil, err
+		}
+
+		id := influxObjectID(created)
+		if id == nil {
+			return nil, nil, fmt.Errorf("%s %q: %w", res.path, name, ErrInfluxUnexpectedResponse)
+		}
+
+		return id, nil, nil
+	}
+
+	id := influxObjectID(existing)
+	if id == nil {
+		return nil, nil, fmt.Errorf("%s %q: %w", res.path, name, ErrInfluxUnexpectedResponse)
+	}
+
+	ignored := make(map[string]struct{}, len(ignore))
+	for _, key := range ignore {
+		ignored[key] = struct{}{}
+	}
+
+	changes := objectDrift("", existing, body, ignored)
+	if force {
+		changes = append(changes, "secrets")
+	}
+
+	if len(changes) == 0 {
+		return id, nil, nil
+	}
+
+	if err := doInfluxRequest(ctx, service, nethttp.MethodPut, res.url(service, string(*id), nil), body, nil); err != nil {
+		return id, nil, err
+	}
+
+	return id, changes, nil
+}
+
+// delete removes the resource identified by id, if it exists.
+func (res influxResource) delete(ctx context.Context, service http.Service, id paradoxv1alpha1.InfluxID) error {
+	if err := doInfluxRequest(ctx, service, nethttp.MethodDelete, res.url(service, string(id), nil), nil, nil); err != nil && !isInfluxNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// toInfluxObject converts v into its JSON representation.
+func toInfluxObject(v interface{}) (influxObject, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj influxObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// influxObjectID returns the identifier of obj, or nil when it has none.
+func influxObjectID(obj influxObject) *paradoxv1alpha1.InfluxID {
+	id, ok := obj["id"].(string)
+	if !ok || id == "" {
+		return nil
+	}
+
+	return fromStringPtr[paradoxv1alpha1.InfluxID](&id)
+}
+
+// objectDrift describes the path of each value of desired which differs within
+// existing. Only the fields declared by desired are compared, as Influx adds
+// fields of its own, and missing values are equal to the zero value.
+func objectDrift(path string, existing, desired interface{}, ignore map[string]struct{}) (changes []string) {
+	switch desired := desired.(type) {
+	case map[string]interface{}:
+		existing, _ := existing.(map[string]interface{})
+
+		keys := make([]string, 0, len(desired))
+		for key := range desired {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if _, ok := ignore[key]; ok {
+				continue
+			}
+
+			changes = append(changes, objectDrift(joinPath(path, key), existing[key], desired[key], ignore)...)
+		}
+
+		return changes
+	case []interface{}:
+		existing, _ := existing.([]interface{})
+		if len(existing) != len(desired) {
+			return []string{path}
+		}
+
+		for i := range desired {
+			if len(objectDrift(path, existing[i], desired[i], ignore)) > 0 {
+				return []string{path}
+			}
+		}
+
+		return nil
+	default:
+		if isZeroValue(existing) && isZeroValue(desired) {
+			return nil
+		}
+
+		if !reflect.DeepEqual(existing, desired) {
+			return []string{path}
+		}
+
+		return nil
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// isZeroValue returns true for JSON values which are equivalent to an omitted value.
+func isZeroValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
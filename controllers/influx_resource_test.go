@@ -0,0 +1,142 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestObjectDrift(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		desired  string
+		ignore   []string
+		want     []string
+	}{
+		{
+			name:     "equal",
+			existing: `{"name": "cpu", "every": "1m"}`,
+			desired:  `{"name": "cpu", "every": "1m"}`,
+		},
+		{
+			name:     "fields added by influx",
+			existing: `{"id": "0000000000000001", "name": "cpu", "createdAt": "2022-06-01T12:00:00Z", "links": {"self": "/api/v2/checks/0000000000000001"}}`,
+			desired:  `{"name": "cpu"}`,
+		},
+		{
+			name:     "changed values sorted by path",
+			existing: `{"name": "cpu", "every": "1m", "description": "usage"}`,
+			desired:  `{"name": "cpu", "every": "5m", "description": "load"}`,
+			want:     []string{"description", "every"},
+		},
+		{
+			name:     "omitted zero values",
+			existing: `{"name": "cpu"}`,
+			desired:  `{"name": "cpu", "description": "", "offset": 0, "activeStatus": false, "tags": [], "labels": {}, "query": null}`,
+		},
+		{
+			name:     "zero values omitted by desired",
+			existing: `{"name": "cpu", "description": "", "offset": 0, "tags": []}`,
+			desired:  `{"name": "cpu", "description": ""}`,
+		},
+		{
+			name:     "value removed",
+			existing: `{"name": "cpu", "description": "usage"}`,
+			desired:  `{"name": "cpu", "description": ""}`,
+			want:     []string{"description"},
+		},
+		{
+			name:     "value added",
+			existing: `{"name": "cpu"}`,
+			desired:  `{"name": "cpu", "offset": 30}`,
+			want:     []string{"offset"},
+		},
+		{
+			name:     "type changed",
+			existing: `{"level": "CRIT"}`,
+			desired:  `{"level": 2}`,
+			want:     []string{"level"},
+		},
+		{
+			name:     "nested objects",
+			existing: `{"query": {"text": "from(bucket: \"a\")", "editMode": "advanced"}}`,
+			desired:  `{"query": {"text": "from(bucket: \"b\")"}}`,
+			want:     []string{"query.text"},
+		},
+		{
+			name:     "equal arrays of objects",
+			existing: `{"thresholds": [{"type": "greater", "level": "CRIT", "value": 90, "allValues": false}, {"type": "lesser", "level": "OK", "value": 10}]}`,
+			desired:  `{"thresholds": [{"type": "greater", "level": "CRIT", "value": 90}, {"type": "lesser", "level": "OK", "value": 10, "allValues": false}]}`,
+		},
+		{
+			name:     "array element changed",
+			existing: `{"thresholds": [{"type": "greater", "level": "CRIT", "value": 90}, {"type": "lesser", "level": "OK", "value": 10}]}`,
+			desired:  `{"thresholds": [{"type": "greater", "level": "CRIT", "value": 90}, {"type": "lesser", "level": "OK", "value": 20}]}`,
+			want:     []string{"thresholds"},
+		},
+		{
+			name:     "array reordered",
+			existing: `{"tags": [{"key": "host", "value": "a"}, {"key": "region", "value": "eu"}]}`,
+			desired:  `{"tags": [{"key": "region", "value": "eu"}, {"key": "host", "value": "a"}]}`,
+			want:     []string{"tags"},
+		},
+		{
+			name:     "array length changed",
+			existing: `{"query": {"builderConfig": {"tags": [{"key": "_measurement", "values": ["cpu"]}]}}}`,
+			desired:  `{"query": {"builderConfig": {"tags": [{"key": "_measurement", "values": ["cpu", "mem"]}]}}}`,
+			want:     []string{"query.builderConfig.tags"},
+		},
+		{
+			name:     "ignored keys",
+			existing: `{"name": "pagerduty", "routingKey": {"key": "0000000000000001-routing-key"}}`,
+			desired:  `{"name": "pagerduty", "routingKey": {"value": "secret"}}`,
+			ignore:   []string{"routingKey"},
+		},
+		{
+			name:     "nested ignored keys",
+			existing: `{"name": "http", "auth": {"method": "basic", "password": {"key": "0000000000000001-password"}}}`,
+			desired:  `{"name": "http", "auth": {"method": "bearer", "password": "secret"}}`,
+			ignore:   []string{"password"},
+			want:     []string{"auth.method"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var existing, desired interface{}
+			if err := json.Unmarshal([]byte(tt.existing), &existing); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := json.Unmarshal([]byte(tt.desired), &desired); err != nil {
+				t.Fatal(err)
+			}
+
+			ignore := make(map[string]struct{}, len(tt.ignore))
+			for _, key := range tt.ignore {
+				ignore[key] = struct{}{}
+			}
+
+			if got := objectDrift("", existing, desired, ignore); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("objectDrift() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
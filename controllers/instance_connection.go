@@ -0,0 +1,256 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// defaultInstanceTimeout is the timeout of each request sent to an instance
+// which does not declare one, matching the default of the Influx client.
+const defaultInstanceTimeout = 20 * time.Second
+
+// ErrInvalidCABundle is returned when a referenced CA bundle contains no certificates.
+var ErrInvalidCABundle = errors.New("CA bundle contains no PEM encoded certificates")
+
+// instanceConnection is the connection configuration of an instance, with every
+// referenced Secret and ConfigMap resolved.
+type instanceConnection struct {
+	address   string
+	tlsConfig *tls.Config
+	proxy     *url.URL
+	timeout   time.Duration
+	headers   map[string]string
+	// hash identifies the configuration, including the content of every
+	// referenced Secret and ConfigMap, so that clients are replaced when it changes.
+	hash string
+}
+
+// resolveInstanceConnection returns the connection configuration of instance.
+func resolveInstanceConnection(ctx context.Context, c client.Client, instance *paradoxv1alpha1.Instance) (*instanceConnection, error) {
+	spec := instance.Spec
+	hash := sha256.New()
+
+	conn := &instanceConnection{
+		address: spec.Address,
+		timeout: defaultInstanceTimeout,
+		headers: spec.Headers,
+	}
+
+	fmt.Fprintf(hash, "address=%s\n", spec.Address)
+
+	if spec.Timeout != "" {
+		timeout, err := time.ParseDuration(spec.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("parsing timeout: %w", err)
+		}
+
+		conn.timeout = timeout
+	}
+
+	fmt.Fprintf(hash, "timeout=%s\n", conn.timeout)
+
+	if spec.Proxy != "" {
+		proxy, err := url.Parse(spec.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("parsing proxy: %w", err)
+		}
+
+		conn.proxy = proxy
+		fmt.Fprintf(hash, "proxy=%s\n", spec.Proxy)
+	}
+
+	names := make([]string, 0, len(spec.Headers))
+	for name := range spec.Headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(hash, "header=%s:%s\n", name, spec.Headers[name])
+	}
+
+	if spec.TLS != nil {
+		config, err := resolveTLSConfig(ctx, c, spec.TLS, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		conn.tlsConfig = config
+	}
+
+	conn.hash = hex.EncodeToString(hash.Sum(nil))
+
+	return conn, nil
+}
+
+// resolveTLSConfig returns the TLS configuration defined by spec, writing the
+// content of every referenced object to hash.
+func resolveTLSConfig(ctx context.Context, c client.Client, spec *paradoxv1alpha1.InstanceTLS, hash io.Writer) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: spec.InsecureSkipVerify,
+		ServerName:         spec.ServerName,
+	}
+
+	fmt.Fprintf(hash, "insecure=%t\nserverName=%s\n", spec.InsecureSkipVerify, spec.ServerName)
+
+	var bundles [][]byte
+
+	if ref := spec.CASecretRef; ref != nil {
+		var secret corev1.Secret
+		if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+			return nil, fmt.Errorf("CA secret: %w", err)
+		}
+
+		bundle, ok := secret.Data[ref.Key]
+		if !ok {
+			return nil, fmt.Errorf("CA secret '%s/%s' has no key %s", ref.Namespace, ref.Name, ref.Key)
+		}
+
+		bundles = append(bundles, bundle)
+	}
+
+	if ref := spec.CAConfigMapRef; ref != nil {
+		var configMap corev1.ConfigMap
+		if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &configMap); err != nil {
+			return nil, fmt.Errorf("CA config map: %w", err)
+		}
+
+		bundle, ok := configMap.Data[ref.Key]
+		if !ok {
+			return nil, fmt.Errorf("CA config map '%s/%s' has no key %s", ref.Namespace, ref.Name, ref.Key)
+		}
+
+		bundles = append(bundles, []byte(bundle))
+	}
+
+	if len(bundles) > 0 {
+		config.RootCAs = x509.NewCertPool()
+
+		for _, bundle := range bundles {
+			if !config.RootCAs.AppendCertsFromPEM(bundle) {
+				return nil, ErrInvalidCABundle
+			}
+
+			_, _ = hash.Write(bundle)
+		}
+	}
+
+	if ref := spec.ClientCertificateSecretRef; ref != nil {
+		var secret corev1.Secret
+		if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+			return nil, fmt.Errorf("client certificate secret: %w", err)
+		}
+
+		certificate, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return nil, fmt.Errorf("client certificate secret '%s/%s': %w", ref.Namespace, ref.Name, err)
+		}
+
+		config.Certificates = []tls.Certificate{certificate}
+
+		_, _ = hash.Write(secret.Data[corev1.TLSCertKey])
+		_, _ = hash.Write(secret.Data[corev1.TLSPrivateKeyKey])
+	}
+
+	return config, nil
+}
+
+// newClient returns a client for the instance authorized using token.
+// Closing the client releases the connections of its transport.
+func (conn *instanceConnection) newClient(token string) influxdb.Client {
+	proxy := nethttp.ProxyFromEnvironment
+	if conn.proxy != nil {
+		proxy = nethttp.ProxyURL(conn.proxy)
+	}
+
+	transport := &nethttp.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		TLSClientConfig:     conn.tlsConfig,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 100,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
+	httpClient := &nethttp.Client{
+		Timeout:   conn.timeout,
+		Transport: &headerTransport{headers: conn.headers, next: transport},
+	}
+
+	return &connectionClient{
+		Client: influxdb.NewClientWithOptions(conn.address, token,
+			influxdb.DefaultOptions().SetHTTPClient(httpClient)),
+		httpClient: httpClient,
+	}
+}
+
+// headerTransport adds a set of headers to every request.
+type headerTransport struct {
+	headers map[string]string
+	next    *nethttp.Transport
+}
+
+func (t *headerTransport) RoundTrip(req *nethttp.Request) (*nethttp.Response, error) {
+	if len(t.headers) > 0 {
+		req = req.Clone(req.Context())
+		for name, value := range t.headers {
+			req.Header.Set(name, value)
+		}
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+func (t *headerTransport) CloseIdleConnections() {
+	t.next.CloseIdleConnections()
+}
+
+// connectionClient is a client which owns the HTTP client it sends requests with.
+type connectionClient struct {
+	influxdb.Client
+	httpClient *nethttp.Client
+}
+
+func (c *connectionClient) Close() {
+	c.Client.Close()
+	c.httpClient.CloseIdleConnections()
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// testCertificate returns a PEM encoded self-signed certificate and its key.
+func testCertificate(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "paradox"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestResolveInstanceConnection(t *testing.T) {
+	caPEM, _ := testCertificate(t)
+	otherCAPEM, _ := testCertificate(t)
+	certPEM, keyPEM := testCertificate(t)
+
+	c := newFakeClient(t,
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "influx", Name: "ca"},
+			Data:       map[string][]byte{"ca.crt": caPEM, "other.crt": otherCAPEM, "invalid.crt": []byte("not a certificate")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "influx", Name: "ca"},
+			Data:       map[string]string{"ca.crt": string(caPEM)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "influx", Name: "client"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+		},
+	)
+
+	caSecret := func(key string) *paradoxv1alpha1.SecretRef {
+		return &paradoxv1alpha1.SecretRef{Namespace: "influx", Name: "ca", Key: key}
+	}
+
+	tests := []struct {
+		name        string
+		spec        paradoxv1alpha1.InstanceSpec
+		wantTimeout time.Duration
+		wantProxy   string
+		wantTLS     bool
+		wantRoots   bool
+		wantCerts   int
+		wantErr     error
+		wantAnyErr  bool
+	}{
+		{
+			name:        "defaults",
+			spec:        paradoxv1alpha1.InstanceSpec{Address: "http://influx:8086"},
+			wantTimeout: defaultInstanceTimeout,
+		},
+		{
+			name:        "timeout and proxy",
+			spec:        paradoxv1alpha1.InstanceSpec{Address: "http://influx:8086", Timeout: "45s", Proxy: "http://proxy:3128"},
+			wantTimeout: 45 * time.Second,
+			wantProxy:   "http://proxy:3128",
+		},
+		{
+			name:       "invalid timeout",
+			spec:       paradoxv1alpha1.InstanceSpec{Address: "http://influx:8086", Timeout: "soon"},
+			wantAnyErr: true,
+		},
+		{
+			name: "CA from secret and config map with client certificate",
+			spec: paradoxv1alpha1.InstanceSpec{Address: "https://influx:8086", TLS: &paradoxv1alpha1.InstanceTLS{
+				CASecretRef:                caSecret("ca.crt"),
+				CAConfigMapRef:             &paradoxv1alpha1.ConfigMapRef{Namespace: "influx", Name: "ca", Key: "ca.crt"},
+				ClientCertificateSecretRef: &paradoxv1alpha1.ObjectRef{Namespace: "influx", Name: "client"},
+			}},
+			wantTimeout: defaultInstanceTimeout,
+			wantTLS:     true,
+			wantRoots:   true,
+			wantCerts:   1,
+		},
+		{
+			name: "insecure",
+			spec: paradoxv1alpha1.InstanceSpec{Address: "https://influx:8086", TLS: &paradoxv1alpha1.InstanceTLS{
+				InsecureSkipVerify: true,
+			}},
+			wantTimeout: defaultInstanceTimeout,
+			wantTLS:     true,
+		},
+		{
+			name: "CA secret missing key",
+			spec: paradoxv1alpha1.InstanceSpec{Address: "https://influx:8086", TLS: &paradoxv1alpha1.InstanceTLS{
+				CASecretRef: caSecret("missing.crt"),
+			}},
+			wantAnyErr: true,
+		},
+		{
+			name: "CA bundle without certificates",
+			spec: paradoxv1alpha1.InstanceSpec{Address: "https://influx:8086", TLS: &paradoxv1alpha1.InstanceTLS{
+				CASecretRef: caSecret("invalid.crt"),
+			}},
+			wantErr: ErrInvalidCABundle,
+		},
+		{
+			name: "client certificate secret missing",
+			spec: paradoxv1alpha1.InstanceSpec{Address: "https://influx:8086", TLS: &paradoxv1alpha1.InstanceTLS{
+				ClientCertificateSecretRef: &paradoxv1alpha1.ObjectRef{Namespace: "influx", Name: "missing"},
+			}},
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := &paradoxv1alpha1.Instance{Spec: tt.spec}
+
+			conn, err := resolveInstanceConnection(context.Background(), c, instance)
+			if tt.wantErr != nil || tt.wantAnyErr {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("resolveInstanceConnection() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("resolveInstanceConnection() error = %v", err)
+			}
+
+			if conn.address != tt.spec.Address || conn.timeout != tt.wantTimeout {
+				t.Errorf("connection = %s with timeout %s, want %s with timeout %s", conn.address, conn.timeout, tt.spec.Address, tt.wantTimeout)
+			}
+
+			if proxy := conn.proxy; (proxy == nil && tt.wantProxy != "") || (proxy != nil && proxy.String() != tt.wantProxy) {
+				t.Errorf("connection proxy = %v, want %q", proxy, tt.wantProxy)
+			}
+
+			if (conn.tlsConfig != nil) != tt.wantTLS {
+				t.Fatalf("connection TLS configured = %v, want %v", conn.tlsConfig != nil, tt.wantTLS)
+			}
+
+			if conn.tlsConfig == nil {
+				return
+			}
+
+			if (conn.tlsConfig.RootCAs != nil) != tt.wantRoots {
+				t.Errorf("connection root CAs configured = %v, want %v", conn.tlsConfig.RootCAs != nil, tt.wantRoots)
+			}
+
+			if len(conn.tlsConfig.Certificates) != tt.wantCerts {
+				t.Errorf("connection has %d client certificates, want %d", len(conn.tlsConfig.Certificates), tt.wantCerts)
+			}
+
+			if conn.tlsConfig.InsecureSkipVerify != tt.spec.TLS.InsecureSkipVerify {
+				t.Errorf("connection skips verification = %v", conn.tlsConfig.InsecureSkipVerify)
+			}
+		})
+	}
+
+	// the hash identifies the configuration, including referenced content
+	hash := func(spec paradoxv1alpha1.InstanceSpec) string {
+		conn, err := resolveInstanceConnection(context.Background(), c, &paradoxv1alpha1.Instance{Spec: spec})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return conn.hash
+	}
+
+	base := paradoxv1alpha1.InstanceSpec{
+		Address: "https://influx:8086",
+		Headers: map[string]string{"X-Tenant": "macro", "X-Scope": "metrics"},
+		TLS:     &paradoxv1alpha1.InstanceTLS{CASecretRef: caSecret("ca.crt")},
+	}
+
+	if hash(base) != hash(base) {
+		t.Error("hash differs for the same configuration")
+	}
+
+	for name, spec := range map[string]paradoxv1alpha1.InstanceSpec{
+		"address": {Address: "https://other:8086", Headers: base.Headers, TLS: base.TLS},
+		"headers": {Address: base.Address, Headers: map[string]string{"X-Tenant": "other"}, TLS: base.TLS},
+		"timeout": {Address: base.Address, Headers: base.Headers, TLS: base.TLS, Timeout: "1m"},
+		"CA":      {Address: base.Address, Headers: base.Headers, TLS: &paradoxv1alpha1.InstanceTLS{CASecretRef: caSecret("other.crt")}},
+	} {
+		if hash(spec) == hash(base) {
+			t.Errorf("hash unchanged when the %s changed", name)
+		}
+	}
+}
+
+func TestInstanceConnectionClient(t *testing.T) {
+	var received nethttp.Header
+	server := httptest.NewTLSServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(nethttp.StatusNoContent)
+	}))
+	defer server.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	c := newFakeClient(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "influx", Name: "ca"},
+		Data:       map[string]string{"ca.crt": string(caPEM)},
+	})
+
+	tests := []struct {
+		name    string
+		tls     *paradoxv1alpha1.InstanceTLS
+		wantErr bool
+	}{
+		{
+			name:    "unknown authority",
+			wantErr: true,
+		},
+		{
+			name: "trusted CA",
+			tls: &paradoxv1alpha1.InstanceTLS{
+				CAConfigMapRef: &paradoxv1alpha1.ConfigMapRef{Namespace: "influx", Name: "ca", Key: "ca.crt"},
+			},
+		},
+		{
+			name: "insecure",
+			tls:  &paradoxv1alpha1.InstanceTLS{InsecureSkipVerify: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = nil
+
+			conn, err := resolveInstanceConnection(context.Background(), c, &paradoxv1alpha1.Instance{Spec: paradoxv1alpha1.InstanceSpec{
+				Address: server.URL,
+				Headers: map[string]string{"X-Tenant": "macro"},
+				TLS:     tt.tls,
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			iclient := conn.newClient("secret")
+			defer iclient.Close()
+
+			ok, err := iclient.Ping(context.Background())
+			if (err != nil || !ok) != tt.wantErr {
+				t.Fatalf("Ping() = %v, %v, wantErr %v", ok, err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got := received.Get("X-Tenant"); got != "macro" {
+				t.Errorf("request header X-Tenant = %q, want %q", got, "macro")
+			}
+		})
+	}
+}
+
+func TestInstanceConnectionProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(nethttp.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	conn, err := resolveInstanceConnection(context.Background(), newFakeClient(t), &paradoxv1alpha1.Instance{Spec: paradoxv1alpha1.InstanceSpec{
+		Address: "http://influx.invalid:8086",
+		Proxy:   proxy.URL,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iclient := conn.newClient("secret")
+	defer iclient.Close()
+
+	if ok, err := iclient.Ping(context.Background()); err != nil || !ok {
+		t.Fatalf("Ping() = %v, %v", ok, err)
+	}
+
+	if want := "http://influx.invalid:8086/ping"; proxied != want {
+		t.Errorf("proxy received request for %q, want %q", proxied, want)
+	}
+}
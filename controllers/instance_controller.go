@@ -18,45 +18,369 @@ package controllers
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
 )
 
+const (
+	// DefaultInstanceProbeInterval is the interval between instance probes
+	// used when InstanceReconciler.ProbeInterval is not set.
+	DefaultInstanceProbeInterval = time.Minute
+
+	instanceProbeTimeout = 10 * time.Second
+
+	influxBuildHeader   = "X-Influxdb-Build"
+	influxVersionHeader = "X-Influxdb-Version"
+
+	// operatorTokenSecretKey is the key of the generated operator token Secret
+	// under which the token is stored.
+	operatorTokenSecretKey = "token"
+	operatorTokenBytes     = 48
+)
+
+// ErrOperatorTokenSecretNotManaged is returned when the Secret named to hold the
+// operator token of an instance exists but is not labelled as belonging to it.
+var ErrOperatorTokenSecretNotManaged = errors.New("secret is not labelled as holding the operator token of the instance")
+
 // InstanceReconciler reconciles a Instance object
 type InstanceReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Clients is the pool of Influx clients shared by every reconciler.
+	// Clients for an instance are closed once it is deleted.
+	Clients *ClientPool
+
+	// ProbeInterval is the interval at which each instance is probed.
+	ProbeInterval time.Duration
 }
 
 //+kubebuilder:rbac:groups=paradox.macro.re,resources=instances,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=paradox.macro.re,resources=instances/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=paradox.macro.re,resources=instances/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the Instance object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
+// Each Instance is probed for reachability, health, version and setup state,
+// the results are recorded in its status and the probe is scheduled to repeat.
+// Instances which declare onboarding and report that they have not yet been
+// set up are onboarded, with the resulting operator token stored in a Secret.
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
 func (r *InstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = log.FromContext(ctx)
+	log := log.FromContext(ctx)
+
+	var instance paradoxv1alpha1.Instance
+	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Clients.CloseInstance(req.NamespacedName)
+
+			return ctrl.Result{}, nil
+		}
+
+		log.Error(err, "unable to fetch instance")
+
+		return ctrl.Result{}, err
+	}
+
+	log = log.WithValues("instance", instance)
+
+	previous := instance.Status
+
+	conn, err := resolveInstanceConnection(ctx, r.Client, &instance)
+	if err != nil {
+		now := metav1.Now()
+		instance.Status = paradoxv1alpha1.InstanceStatus{
+			LastProbeTime: &now,
+			Message:       fmt.Sprintf("resolving connection: %s", err),
+		}
+	} else {
+		instance.Status = probeInstance(ctx, conn)
+	}
+
+	instance.Status.OperatorTokenSecret = previous.OperatorTokenSecret
+
+	// events are only recorded when reachability changes, rather than on every probe
+	switch {
+	case !instance.Status.Reachable && (previous.Reachable || previous.LastProbeTime == nil):
+		r.Recorder.Event(&instance, corev1.EventTypeWarning, "Unreachable", instance.Status.Message)
+	case instance.Status.Reachable && !previous.Reachable && previous.LastProbeTime != nil:
+		r.Recorder.Event(&instance, corev1.EventTypeNormal, "Reachable", "instance is reachable again")
+	}
+
+	var onboardErr error
+	if conn != nil && instance.Spec.Onboarding != nil && instance.Status.Onboarded != nil && !*instance.Status.Onboarded {
+		if onboardErr = r.onboard(ctx, &instance, conn); onboardErr != nil {
+			log.Error(onboardErr, "failed to onboard instance")
+			r.Recorder.Event(&instance, corev1.EventTypeWarning, "OnboardingFailed", onboardErr.Error())
+
+			instance.Status.Message = onboardErr.Error()
+		} else {
+			r.Recorder.Eventf(&instance, corev1.EventTypeNormal, "Onboarded",
+				"operator token stored in secret %s/%s", instance.Status.OperatorTokenSecret.Namespace, instance.Status.OperatorTokenSecret.Name)
+		}
+	}
+
+	if err := r.Status().Update(ctx, &instance); err != nil {
+		log.Error(err, "failed to update status")
+
+		return ctrl.Result{}, err
+	}
+
+	if onboardErr != nil {
+		return ctrl.Result{}, onboardErr
+	}
+
+	if !instance.Status.Reachable {
+		log.V(1).Info("instance unreachable", "message", instance.Status.Message)
+	}
+
+	return ctrl.Result{RequeueAfter: r.probeInterval()}, nil
+}
+
+func (r *InstanceReconciler) probeInterval() time.Duration {
+	if r.ProbeInterval > 0 {
+		return r.ProbeInterval
+	}
+
+	return DefaultInstanceProbeInterval
+}
+
+// probeInstance calls the ping, health, ready and setup endpoints of the
+// Influx instance reached through conn and reports the observed state.
+// The probe endpoints do not require authorization.
+func probeInstance(ctx context.Context, conn *instanceConnection) paradoxv1alpha1.InstanceStatus {
+	ctx, cancel := context.WithTimeout(ctx, instanceProbeTimeout)
+	defer cancel()
+
+	now := metav1.Now()
+	status := paradoxv1alpha1.InstanceStatus{
+		LastProbeTime: &now,
+	}
+
+	iclient := conn.newClient("")
+	defer iclient.Close()
+
+	api := domain.NewClientWithResponses(iclient.HTTPService())
+
+	ping, err := api.GetPingWithResponse(ctx)
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+
+	status.Reachable = true
+	status.Build = paradoxv1alpha1.InstanceBuild(ping.HTTPResponse.Header.Get(influxBuildHeader))
+	status.Version = ping.HTTPResponse.Header.Get(influxVersionHeader)
+
+	health, err := iclient.Health(ctx)
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+
+	status.Health = string(health.Status)
+	if health.Status != domain.HealthCheckStatusPass && health.Message != nil {
+		status.Message = *health.Message
+	}
+
+	if status.Version == "" && health.Version != nil {
+		status.Version = *health.Version
+	}
+
+	ready, err := iclient.Ready(ctx)
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+
+	status.Ready = ready.Status != nil && *ready.Status == domain.ReadyStatusReady
+
+	// InfluxDB Cloud does not expose the onboarding endpoint
+	if status.Build == paradoxv1alpha1.InstanceBuildCloud {
+		return status
+	}
+
+	setup, err := api.GetSetupWithResponse(ctx, &domain.GetSetupParams{})
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+
+	if setup.JSON200 != nil && setup.JSON200.Allowed != nil {
+		onboarded := !*setup.JSON200.Allowed
+		status.Onboarded = &onboarded
+	}
+
+	return status
+}
+
+// onboard performs the initial setup of instance using its onboarding spec.
+// The operator token is generated up front and stored in a Secret owned by the
+// instance before setup is attempted, so that the token is never lost when a
+// later step fails. Retries reuse the token already stored in the Secret.
+func (r *InstanceReconciler) onboard(ctx context.Context, instance *paradoxv1alpha1.Instance, conn *instanceConnection) error {
+	onboarding := instance.Spec.Onboarding
+
+	password, err := resolveToken(ctx, r.Client, paradoxv1alpha1.InstanceAuthorization{
+		Type:   paradoxv1alpha1.InstanceAuthorizationTypeSecret,
+		Secret: &onboarding.PasswordSecretRef,
+	})
+	if err != nil {
+		return fmt.Errorf("resolving onboarding password: %w", err)
+	}
+
+	body := domain.PostSetupJSONRequestBody{
+		Username: onboarding.Username,
+		Password: &password,
+		Org:      onboarding.Organization,
+		Bucket:   onboarding.Bucket,
+	}
+
+	if onboarding.RetentionPeriod != "" {
+		retention, err := time.ParseDuration(onboarding.RetentionPeriod)
+		if err != nil {
+			return fmt.Errorf("parsing onboarding retention period: %w", err)
+		}
+
+		seconds := int64(retention.Seconds())
+		body.RetentionPeriodSeconds = &seconds
+	}
+
+	secret, err := r.operatorTokenSecret(ctx, instance)
+	if err != nil {
+		return err
+	}
+
+	token := string(secret.Data[operatorTokenSecretKey])
+	body.Token = &token
+
+	iclient := conn.newClient("")
+	defer iclient.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, instanceProbeTimeout)
+	defer cancel()
+
+	resp, err := domain.NewClientWithResponses(iclient.HTTPService()).
+		PostSetupWithResponse(ctx, &domain.PostSetupParams{}, body)
+	if err != nil {
+		return err
+	}
+
+	if resp.JSONDefault != nil {
+		return domain.ErrorToHTTPError(resp.JSONDefault, resp.StatusCode())
+	}
+
+	if resp.JSON201 == nil {
+		return fmt.Errorf("%w: setup returned %s", ErrInfluxUnexpectedResponse, resp.Status())
+	}
+
+	onboarded := true
+	instance.Status.Onboarded = &onboarded
+	instance.Status.OperatorTokenSecret = &paradoxv1alpha1.SecretRef{
+		Namespace: secret.Namespace,
+		Name:      secret.Name,
+		Key:       operatorTokenSecretKey,
+	}
+
+	return nil
+}
+
+// operatorTokenSecret fetches the Secret holding the operator token of instance,
+// creating it with a newly generated token when it does not yet exist. An existing
+// Secret is only used when labelled for instance, so that an unrelated Secret of
+// the same name is neither reused nor exposed as the operator token.
+func (r *InstanceReconciler) operatorTokenSecret(ctx context.Context, instance *paradoxv1alpha1.Instance) (*corev1.Secret, error) {
+	name := instance.Spec.Onboarding.TokenSecretName
+	if name == "" {
+		name = instance.Name + "-operator-token"
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: name}, secret)
+	if err == nil {
+		labels := secret.GetLabels()
+		if labels[instanceNameLabel] != instance.Name || labels[instanceNamespaceLabel] != instance.Namespace || labels[authorizationNameLabel] != "" {
+			return nil, fmt.Errorf("operator token secret %s/%s: %w", secret.Namespace, secret.Name, ErrOperatorTokenSecretNotManaged)
+		}
+
+		if len(secret.Data[operatorTokenSecretKey]) == 0 {
+			return nil, fmt.Errorf("operator token secret %s/%s has no key %q", secret.Namespace, secret.Name, operatorTokenSecretKey)
+		}
+
+		return secret, nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// the token cannot be recovered once lost, as an instance can only be
+	// onboarded once, so the secret is linked to the instance by label rather
+	// than owned by it and is never garbage collected along with it
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: instance.Namespace,
+			Name:      name,
+			Labels: map[string]string{
+				instanceNameLabel:      instance.Name,
+				instanceNamespaceLabel: instance.Namespace,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			operatorTokenSecretKey: []byte(token),
+		},
+	}
+
+	if err := r.Create(ctx, secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
 
-	// TODO(user): your logic here
+// generateToken returns a random token suitable for use as an Influx operator token.
+func generateToken() (string, error) {
+	buf := make([]byte, operatorTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
 
-	return ctrl.Result{}, nil
+	return base64.URLEncoding.EncodeToString(buf), nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// Status updates are filtered out, as every probe records a new probe time
+// and would otherwise immediately trigger another probe.
 func (r *InstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&paradoxv1alpha1.Instance{}).
+		For(&paradoxv1alpha1.Instance{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
@@ -0,0 +1,261 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+func TestOperatorTokenSecret(t *testing.T) {
+	instance := &paradoxv1alpha1.Instance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "influx", Name: "primary"},
+		Spec: paradoxv1alpha1.InstanceSpec{
+			Onboarding: &paradoxv1alpha1.InstanceOnboarding{Username: "admin", Organization: "macro", Bucket: "default"},
+		},
+	}
+
+	instanceLabels := map[string]string{instanceNameLabel: "primary", instanceNamespaceLabel: "influx"}
+
+	secret := func(labels map[string]string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "influx", Name: "primary-operator-token", Labels: labels},
+			Data:       data,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		existing  *corev1.Secret
+		wantToken string
+		wantErr   error
+		wantAny   bool
+	}{
+		{
+			name: "created",
+		},
+		{
+			name:      "labelled for the instance",
+			existing:  secret(instanceLabels, map[string][]byte{operatorTokenSecretKey: []byte("existing")}),
+			wantToken: "existing",
+		},
+		{
+			name:     "unlabelled",
+			existing: secret(nil, map[string][]byte{operatorTokenSecretKey: []byte("unrelated")}),
+			wantErr:  ErrOperatorTokenSecretNotManaged,
+		},
+		{
+			name:     "labelled for another instance",
+			existing: secret(map[string]string{instanceNameLabel: "secondary", instanceNamespaceLabel: "influx"}, map[string][]byte{operatorTokenSecretKey: []byte("other")}),
+			wantErr:  ErrOperatorTokenSecretNotManaged,
+		},
+		{
+			name: "token target of an authorization",
+			existing: secret(map[string]string{
+				instanceNameLabel: "primary", instanceNamespaceLabel: "influx",
+				authorizationNameLabel: "telegraf", authorizationNamespaceLabel: "default",
+			}, map[string][]byte{operatorTokenSecretKey: []byte("telegraf")}),
+			wantErr: ErrOperatorTokenSecretNotManaged,
+		},
+		{
+			name:     "missing key",
+			existing: secret(instanceLabels, nil),
+			wantAny:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []client.Object
+			if tt.existing != nil {
+				objs = append(objs, tt.existing)
+			}
+
+			c := newFakeClient(t, objs...)
+			r := &InstanceReconciler{Client: c, Scheme: c.Scheme()}
+
+			got, err := r.operatorTokenSecret(context.Background(), instance)
+			if tt.wantErr != nil || tt.wantAny {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("operatorTokenSecret() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("operatorTokenSecret() error = %v", err)
+			}
+
+			token := string(got.Data[operatorTokenSecretKey])
+			if tt.wantToken != "" && token != tt.wantToken {
+				t.Errorf("token = %q, want %q", token, tt.wantToken)
+			}
+
+			if token == "" {
+				t.Error("no token generated")
+			}
+
+			if got.Labels[instanceNameLabel] != "primary" || got.Labels[instanceNamespaceLabel] != "influx" {
+				t.Errorf("secret labels = %v, want those of the instance", got.Labels)
+			}
+		})
+	}
+}
+
+func TestInstanceReconcileEvents(t *testing.T) {
+	// influx serves the probe and setup endpoints of an instance which has
+	// not yet been set up, failing setup when setupStatus is an error
+	influx := func(setupStatus int) *httptest.Server {
+		return httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			switch {
+			case r.URL.Path == "/ping":
+				w.WriteHeader(nethttp.StatusNoContent)
+			case r.URL.Path == "/health":
+				_, _ = w.Write([]byte(`{"name": "influxdb", "status": "pass", "checks": []}`))
+			case r.URL.Path == "/ready":
+				_, _ = w.Write([]byte(`{"status": "ready"}`))
+			case r.URL.Path == "/api/v2/setup" && r.Method == nethttp.MethodGet:
+				_, _ = w.Write([]byte(`{"allowed": true}`))
+			case r.URL.Path == "/api/v2/setup":
+				w.WriteHeader(setupStatus)
+
+				if setupStatus == nethttp.StatusCreated {
+					_, _ = w.Write([]byte(`{}`))
+				} else {
+					_, _ = w.Write([]byte(`{"code": "internal error", "message": "setup failed"}`))
+				}
+			default:
+				w.WriteHeader(nethttp.StatusNotFound)
+			}
+		}))
+	}
+
+	unreachable := httptest.NewServer(nethttp.NotFoundHandler())
+	unreachable.Close()
+
+	probed := metav1.Now()
+	onboarding := &paradoxv1alpha1.InstanceOnboarding{
+		Username:          "admin",
+		PasswordSecretRef: paradoxv1alpha1.SecretRef{Namespace: "influx", Name: "admin", Key: "password"},
+		Organization:      "macro",
+		Bucket:            "default",
+	}
+
+	tests := []struct {
+		name        string
+		address     string
+		setupStatus int
+		onboarding  *paradoxv1alpha1.InstanceOnboarding
+		previous    paradoxv1alpha1.InstanceStatus
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name:    "first probe unreachable",
+			address: unreachable.URL,
+			want:    []string{"Warning Unreachable"},
+		},
+		{
+			name:     "still unreachable",
+			address:  unreachable.URL,
+			previous: paradoxv1alpha1.InstanceStatus{LastProbeTime: &probed},
+		},
+		{
+			name:     "reachable again",
+			previous: paradoxv1alpha1.InstanceStatus{LastProbeTime: &probed},
+			want:     []string{"Normal Reachable"},
+		},
+		{
+			name:     "still reachable",
+			previous: paradoxv1alpha1.InstanceStatus{LastProbeTime: &probed, Reachable: true},
+		},
+		{
+			name:        "onboarding failed",
+			setupStatus: nethttp.StatusInternalServerError,
+			onboarding:  onboarding,
+			previous:    paradoxv1alpha1.InstanceStatus{LastProbeTime: &probed, Reachable: true},
+			want:        []string{"Warning OnboardingFailed"},
+			wantErr:     true,
+		},
+		{
+			name:        "onboarded",
+			setupStatus: nethttp.StatusCreated,
+			onboarding:  onboarding,
+			previous:    paradoxv1alpha1.InstanceStatus{LastProbeTime: &probed, Reachable: true},
+			want:        []string{"Normal Onboarded"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := tt.address
+			if address == "" {
+				server := influx(tt.setupStatus)
+				defer server.Close()
+
+				address = server.URL
+			}
+
+			instance := &paradoxv1alpha1.Instance{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "influx", Name: "primary"},
+				Spec:       paradoxv1alpha1.InstanceSpec{Address: address, Onboarding: tt.onboarding},
+				Status:     tt.previous,
+			}
+
+			c := newFakeClient(t, instance, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "influx", Name: "admin"},
+				Data:       map[string][]byte{"password": []byte("password")},
+			})
+			recorder := record.NewFakeRecorder(10)
+			r := &InstanceReconciler{Client: c, Scheme: c.Scheme(), Recorder: recorder, Clients: NewClientPool()}
+
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "influx", Name: "primary"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			close(recorder.Events)
+
+			var got []string
+			for event := range recorder.Events {
+				fields := strings.Fields(event)
+				got = append(got, strings.Join(fields[:2], " "))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// instanceTarget is an instance targeted by an organization, along with the
+// credential used to authorize requests against it.
+type instanceTarget struct {
+	auth paradoxv1alpha1.InstanceAuthorization
+	// selected is true when the instance was matched by the instance selector,
+	// in which case auth is a template which must be rendered for the instance.
+	selected bool
+}
+
+// authorization returns the credential for the instance identified by key.
+func (t instanceTarget) authorization(key types.NamespacedName) (paradoxv1alpha1.InstanceAuthorization, error) {
+	if !t.selected || t.auth.Secret == nil {
+		return t.auth, nil
+	}
+
+	data := struct {
+		Instance types.NamespacedName
+	}{Instance: key}
+
+	auth := *t.auth.DeepCopy()
+	for _, field := range []*string{&auth.Secret.Namespace, &auth.Secret.Name} {
+		tmpl, err := template.New("").Option("missingkey=error").Parse(*field)
+		if err != nil {
+			return auth, fmt.Errorf("parsing secret template: %w", err)
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return auth, fmt.Errorf("rendering secret template: %w", err)
+		}
+
+		*field = buf.String()
+	}
+
+	return auth, nil
+}
+
+// instanceTargets returns every instance targeted by organization, both by
+// reference and by selector. References take precedence over the selector.
+func instanceTargets(ctx context.Context, c client.Client, organization *paradoxv1alpha1.Organization) (map[types.NamespacedName]instanceTarget, error) {
+	targets := map[types.NamespacedName]instanceTarget{}
+
+	if selector := organization.Spec.InstanceSelector; selector != nil {
+		instances, err := selectInstances(ctx, c, organization.ObjectMeta.Namespace, selector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instance := range instances {
+			targets[client.ObjectKeyFromObject(&instance)] = instanceTarget{auth: selector.Authorization, selected: true}
+		}
+	}
+
+	for namespace, namespacedInstances := range organization.Spec.InstanceRefs {
+		for name, auth := range namespacedInstances {
+			targets[types.NamespacedName{Namespace: namespace, Name: name}] = instanceTarget{auth: auth}
+		}
+	}
+
+	return targets, nil
+}
+
+// selectInstances lists the instances matched by selector, searching the namespace
+// of the organization unless the selector has a namespace selector.
+func selectInstances(ctx context.Context, c client.Client, namespace string, selector *paradoxv1alpha1.InstanceSelector) ([]paradoxv1alpha1.Instance, error) {
+	instanceSelector, err := metav1.LabelSelectorAsSelector(&selector.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("instance selector: %w", err)
+	}
+
+	opts := []client.ListOption{client.MatchingLabelsSelector{Selector: instanceSelector}}
+
+	var namespaces map[string]struct{}
+	if selector.NamespaceSelector == nil {
+		opts = append(opts, client.InNamespace(namespace))
+	} else {
+		namespaceSelector, err := metav1.LabelSelectorAsSelector(selector.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("instance namespace selector: %w", err)
+		}
+
+		var list corev1.NamespaceList
+		if err := c.List(ctx, &list, client.MatchingLabelsSelector{Selector: namespaceSelector}); err != nil {
+			return nil, err
+		}
+
+		namespaces = make(map[string]struct{}, len(list.Items))
+		for _, ns := range list.Items {
+			namespaces[ns.Name] = struct{}{}
+		}
+	}
+
+	var list paradoxv1alpha1.InstanceList
+	if err := c.List(ctx, &list, opts...); err != nil {
+		return nil, err
+	}
+
+	if namespaces == nil {
+		return list.Items, nil
+	}
+
+	instances := list.Items[:0]
+	for _, instance := range list.Items {
+		if _, ok := namespaces[instance.ObjectMeta.Namespace]; ok {
+			instances = append(instances, instance)
+		}
+	}
+
+	return instances, nil
+}
+
+// selectsInstance returns true when the instance selector of organization matches instance.
+func selectsInstance(ctx context.Context, c client.Client, organization *paradoxv1alpha1.Organization, instance client.Object) (bool, error) {
+	selector := organization.Spec.InstanceSelector
+	if selector == nil {
+		return false, nil
+	}
+
+	instanceSelector, err := metav1.LabelSelectorAsSelector(&selector.Selector)
+	if err != nil {
+		return false, err
+	}
+
+	if !instanceSelector.Matches(labels.Set(instance.GetLabels())) {
+		return false, nil
+	}
+
+	if selector.NamespaceSelector == nil {
+		return instance.GetNamespace() == organization.ObjectMeta.Namespace, nil
+	}
+
+	namespaceSelector, err := metav1.LabelSelectorAsSelector(selector.NamespaceSelector)
+	if err != nil {
+		return false, err
+	}
+
+	var namespace corev1.Namespace
+	if err := c.Get(ctx, types.NamespacedName{Name: instance.GetNamespace()}, &namespace); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	return namespaceSelector.Matches(labels.Set(namespace.Labels)), nil
+}
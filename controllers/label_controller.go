@@ -0,0 +1,300 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// LabelReconciler reconciles a Label object
+type LabelReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Clients is the pool of Influx clients shared by every reconciler.
+	Clients *ClientPool
+	// ResyncInterval is the interval at which each label is reconciled
+	// against its target instances, unless overridden by annotation.
+	ResyncInterval time.Duration
+}
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=labels,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=labels/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=labels/finalizers,verbs=update
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations/status,verbs=get
+
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// Labels which already exist are compared against the spec and any drift in
+// their name, color or description is corrected.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *LabelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var label paradoxv1alpha1.Label
+	if err := r.Get(ctx, req.NamespacedName, &label); err != nil {
+		log.Error(err, "unable to fetch label")
+
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	log = log.WithValues("label", label)
+
+	if !label.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := finalize(ctx, r.Client, &label, label.Spec.DeletionPolicy, func() error {
+			return r.deleteInstanceLabels(ctx, &label)
+		}); err != nil {
+			log.Error(err, "failed to finalize label")
+
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if err := syncFinalizer(ctx, r.Client, &label, label.Spec.DeletionPolicy); err != nil {
+		log.Error(err, "failed to update finalizers")
+
+		return ctrl.Result{}, err
+	}
+
+	var organization paradoxv1alpha1.Organization
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: req.NamespacedName.Namespace,
+		Name:      label.Spec.Organization,
+	}, &organization); err != nil {
+		log.Error(err, "unable to fetch organization")
+
+		return ctrl.Result{}, client.IgnoreNotFound(r.updateStatus(ctx, &label, label.Status.Instances, fmt.Errorf("organization %q: %w", label.Spec.Organization, err)))
+	}
+
+	properties := labelProperties(label.Spec)
+
+	var (
+		mu    sync.Mutex
+		drift []string
+	)
+
+	instances, err := reconcileInstances(ctx, r.Client, r.Clients, &organization, label.Status.Instances, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) (*paradoxv1alpha1.InfluxID, error) {
+		namespace, name := instance.ObjectMeta.Namespace, instance.ObjectMeta.Name
+
+		orgID, err := organizationID(&organization, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		labelsAPI := client.LabelsAPI()
+		existing, err := findLabel(ctx, labelsAPI, label.Status.Instances[namespace][name].ID, label.Spec.Name, orgID)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing == nil {
+			// create label if not exists, with only the properties which are set
+
+			create := map[string]string{}
+			for key, value := range properties {
+				if value != "" {
+					create[key] = value
+				}
+			}
+
+			existing, err = labelsAPI.CreateLabel(ctx, &domain.LabelCreateRequest{
+				Name:       label.Spec.Name,
+				OrgID:      orgID,
+				Properties: &domain.LabelCreateRequest_Properties{AdditionalProperties: create},
+			})
+			if err != nil {
+				return nil, err
+			}
+		} else if changes := labelDrift(existing, label.Spec.Name, properties); len(changes) > 0 {
+			// update label if it exists and differs, where empty
+			// properties are removed
+
+			existing.Name = &label.Spec.Name
+			existing.Properties = &domain.Label_Properties{AdditionalProperties: properties}
+
+			existing, err = labelsAPI.UpdateLabel(ctx, existing)
+			if err != nil {
+				return nil, err
+			}
+
+			message := fmt.Sprintf("corrected drift in instance %s/%s: %s", namespace, name, strings.Join(changes, ", "))
+			r.Recorder.Event(&label, corev1.EventTypeNormal, "DriftCorrected", message)
+
+			mu.Lock()
+			drift = append(drift, message)
+			mu.Unlock()
+		}
+
+		return fromStringPtr[paradoxv1alpha1.InfluxID](existing.Id), nil
+	})
+	if err != nil {
+		log.Error(err, "error while configuring instances")
+	}
+
+	setDriftCondition(&label.Status.Conditions, label.Generation, drift)
+
+	return resyncResult(ctx, &label, r.ResyncInterval), r.updateStatus(ctx, &label, instances, err)
+}
+
+// updateStatus records instances along with the conditions derived from reconcileErr
+// in the status of label. The reconcile error is returned unless the status update
+// itself fails.
+func (r *LabelReconciler) updateStatus(ctx context.Context, label *paradoxv1alpha1.Label, instances paradoxv1alpha1.Instances, reconcileErr error) error {
+	if instances == nil {
+		instances = paradoxv1alpha1.Instances{}
+	}
+
+	label.Status.ObservedGeneration = label.Generation
+	label.Status.Instances = instances
+	setConditions(&label.Status.Conditions, label.Generation, instances, reconcileErr)
+
+	if err := r.Status().Update(ctx, label); err != nil {
+		log.FromContext(ctx).Error(err, "failed to update status")
+
+		return err
+	}
+
+	return reconcileErr
+}
+
+// deleteInstanceLabels removes the label from every target instance in which
+// it has previously been recorded, which also removes it from every resource it
+// is assigned to.
+func (r *LabelReconciler) deleteInstanceLabels(ctx context.Context, label *paradoxv1alpha1.Label) error {
+	deletion := instanceDeletion{
+		Client:    r.Client,
+		Clients:   r.Clients,
+		Recorder:  r.Recorder,
+		Object:    label,
+		Noun:      "label",
+		Instances: label.Status.Instances,
+	}
+
+	return deletion.deleteWithin(ctx, label.Spec.Organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client, id paradoxv1alpha1.InfluxID) error {
+		if err := client.LabelsAPI().DeleteLabelWithID(ctx, string(id)); err != nil && !isInfluxNotFound(err) {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// findLabel returns the label previously recorded as id, or else the label named
+// name within the organization identified by orgID, or nil when neither exists.
+func findLabel(ctx context.Context, labelsAPI api.LabelsAPI, id *paradoxv1alpha1.InfluxID, name, orgID string) (*domain.Label, error) {
+	if id != nil {
+		label, err := labelsAPI.FindLabelByID(ctx, string(*id))
+		if err == nil {
+			return label, nil
+		}
+
+		if !isInfluxNotFound(err) {
+			return nil, err
+		}
+	}
+
+	label, err := labelsAPI.FindLabelByName(ctx, orgID, name)
+	if err != nil {
+		if isInfluxNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return label, nil
+}
+
+// labelProperties returns the properties of the label defined by spec, which
+// are displayed by the Influx UI.
+func labelProperties(spec paradoxv1alpha1.LabelSpec) map[string]string {
+	return map[string]string{
+		"color":       spec.Color,
+		"description": spec.Description,
+	}
+}
+
+// labelDrift describes each difference between the existing label and the
+// desired name and properties.
+func labelDrift(existing *domain.Label, name string, properties map[string]string) (changes []string) {
+	if fromPtr(existing.Name) != name {
+		changes = append(changes, fmt.Sprintf("name %q -> %q", fromPtr(existing.Name), name))
+	}
+
+	var current map[string]string
+	if existing.Properties != nil {
+		current = existing.Properties.AdditionalProperties
+	}
+
+	for _, key := range []string{"color", "description"} {
+		if current[key] != properties[key] {
+			changes = append(changes, fmt.Sprintf("%s %q -> %q", key, current[key], properties[key]))
+		}
+	}
+
+	return changes
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LabelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &paradoxv1alpha1.Label{}, orgField, func(rawObj client.Object) []string {
+		label := rawObj.(*paradoxv1alpha1.Label)
+		if label.Spec.Organization == "" {
+			return nil
+		}
+
+		return []string{label.Spec.Organization}
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&paradoxv1alpha1.Label{}, builder.WithPredicates(specOrAnnotationChanged())).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Organization{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.LabelList{}, orgField)),
+		).
+		Complete(r)
+}
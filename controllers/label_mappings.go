@@ -0,0 +1,212 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/http"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// labelsField indexes resources by the name of each Label assigned to them.
+const labelsField = ".spec.labels"
+
+// assignedLabels are the Labels assigned to a resource.
+type assignedLabels []paradoxv1alpha1.Label
+
+// fetchLabels returns the Labels named by names within namespace, each of
+// which must belong to the organization of the resource they are assigned to.
+func fetchLabels(ctx context.Context, c client.Client, namespace, organization string, names []string) (assignedLabels, error) {
+	labels := make(assignedLabels, len(names))
+	for i, name := range names {
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &labels[i]); err != nil {
+			return nil, fmt.Errorf("label %q: %w", name, err)
+		}
+
+		if labels[i].Spec.Organization != organization {
+			return nil, fmt.Errorf("label %q belongs to organization %q rather than %q", name, labels[i].Spec.Organization, organization)
+		}
+	}
+
+	return labels, nil
+}
+
+// ids returns the name of each label keyed by its identifier within instance.
+func (l assignedLabels) ids(instance *paradoxv1alpha1.Instance) (map[string]string, error) {
+	ids := make(map[string]string, len(l))
+	for _, label := range l {
+		id := label.Status.Instances[instance.ObjectMeta.Namespace][instance.ObjectMeta.Name].ID
+		if id == nil {
+			return nil, fmt.Errorf("label %q: %w", label.ObjectMeta.Name, ErrReferenceNotCreated)
+		}
+
+		ids[string(*id)] = label.Spec.Name
+	}
+
+	return ids, nil
+}
+
+// labelMappings are the labels assigned to a resource by the operator within
+// each target instance. They may be used by concurrent instance reconcilers.
+type labelMappings struct {
+	mu       sync.Mutex
+	assigned paradoxv1alpha1.InstanceLabels
+}
+
+// newLabelMappings returns label mappings starting from a copy of those recorded
+// in the status of a resource.
+func newLabelMappings(recorded paradoxv1alpha1.InstanceLabels) *labelMappings {
+	assigned := recorded.DeepCopy()
+	if assigned == nil {
+		assigned = paradoxv1alpha1.InstanceLabels{}
+	}
+
+	return &labelMappings{assigned: assigned}
+}
+
+func (m *labelMappings) get(instance *paradoxv1alpha1.Instance) []paradoxv1alpha1.InfluxID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.assigned.Get(instance.ObjectMeta.Namespace, instance.ObjectMeta.Name)
+}
+
+func (m *labelMappings) set(instance *paradoxv1alpha1.Instance, ids []paradoxv1alpha1.InfluxID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.assigned.Set(instance.ObjectMeta.Namespace, instance.ObjectMeta.Name, ids)
+}
+
+// syncLabelMappings assigns the labels of instance to the resource identified by
+// id within the collection at path, such as buckets. The labels assigned by the
+// operator are recorded in mappings, so that those which are no longer declared are
+// removed without disturbing labels assigned by other means, such as the Influx UI.
+// Labels are left unmanaged when none are declared or recorded. A description of
+// each recorded label which had been removed from the resource, and so was
+// assigned again, is returned.
+func (l assignedLabels) syncLabelMappings(
+	ctx context.Context,
+	service http.Service,
+	instance *paradoxv1alpha1.Instance,
+	path string,
+	id paradoxv1alpha1.InfluxID,
+	mappings *labelMappings,
+) (drift []string, err error) {
+	previous := mappings.get(instance)
+	if len(l) == 0 && len(previous) == 0 {
+		return nil, nil
+	}
+
+	desired, err := l.ids(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	labelsURL := service.ServerAPIURL() + path + "/" + url.PathEscape(string(id)) + "/labels"
+
+	var existing struct {
+		Labels []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	if err := doInfluxRequest(ctx, service, nethttp.MethodGet, labelsURL, nil, &existing); err != nil {
+		return nil, fmt.Errorf("listing labels: %w", err)
+	}
+
+	recorded := make(map[string]struct{}, len(previous))
+	for _, labelID := range previous {
+		recorded[string(labelID)] = struct{}{}
+	}
+
+	// the labels assigned so far are recorded even when a request fails
+	assigned := make(map[string]struct{}, len(recorded)+len(desired))
+	defer func() {
+		ids := make([]paradoxv1alpha1.InfluxID, 0, len(assigned))
+		for labelID := range assigned {
+			ids = append(ids, paradoxv1alpha1.InfluxID(labelID))
+		}
+
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		mappings.set(instance, ids)
+	}()
+
+	current := make(map[string]struct{}, len(existing.Labels))
+	for _, label := range existing.Labels {
+		current[label.ID] = struct{}{}
+
+		_, isRecorded := recorded[label.ID]
+		if _, ok := desired[label.ID]; ok {
+			if isRecorded {
+				assigned[label.ID] = struct{}{}
+			}
+
+			continue
+		}
+
+		if !isRecorded {
+			continue
+		}
+
+		if err := doInfluxRequest(ctx, service, nethttp.MethodDelete, labelsURL+"/"+url.PathEscape(label.ID), nil, nil); err != nil && !isInfluxNotFound(err) {
+			assigned[label.ID] = struct{}{}
+
+			return drift, err
+		}
+	}
+
+	added := make([]string, 0, len(desired))
+	for labelID := range desired {
+		if _, ok := current[labelID]; !ok {
+			added = append(added, labelID)
+		}
+	}
+
+	sort.Strings(added)
+
+	for _, labelID := range added {
+		if err := doInfluxRequest(ctx, service, nethttp.MethodPost, labelsURL, map[string]string{"labelID": labelID}, nil); err != nil {
+			return drift, err
+		}
+
+		assigned[labelID] = struct{}{}
+
+		if _, ok := recorded[labelID]; ok {
+			drift = append(drift, fmt.Sprintf("label %q added", desired[labelID]))
+		}
+	}
+
+	return drift, nil
+}
+
+// indexLabels indexes objects of the same type as obj by the names of the
+// Labels returned by labels.
+func indexLabels(mgr ctrl.Manager, obj client.Object, labels func(client.Object) []string) error {
+	return mgr.GetFieldIndexer().IndexField(context.Background(), obj, labelsField, labels)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+func TestSyncLabelMappings(t *testing.T) {
+	instance := &paradoxv1alpha1.Instance{ObjectMeta: metav1.ObjectMeta{Namespace: "influx", Name: "primary"}}
+
+	label := func(name string, id paradoxv1alpha1.InfluxID) paradoxv1alpha1.Label {
+		return paradoxv1alpha1.Label{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       paradoxv1alpha1.LabelSpec{Name: name},
+			Status: paradoxv1alpha1.LabelStatus{Instances: paradoxv1alpha1.Instances{
+				"influx": {"primary": paradoxv1alpha1.ResourceInstance{ID: &id}},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		labels      assignedLabels
+		recorded    []paradoxv1alpha1.InfluxID
+		existing    []string
+		wantDrift   []string
+		wantAdded   []string
+		wantDeleted []string
+		wantMapped  []paradoxv1alpha1.InfluxID
+	}{
+		{
+			name: "unmanaged",
+		},
+		{
+			name:       "assigned",
+			labels:     assignedLabels{label("env", "01"), label("team", "02")},
+			existing:   []string{"01"},
+			wantAdded:  []string{"02"},
+			wantMapped: []paradoxv1alpha1.InfluxID{"02"},
+		},
+		{
+			name:       "recorded label removed from the resource",
+			labels:     assignedLabels{label("env", "01")},
+			recorded:   []paradoxv1alpha1.InfluxID{"01"},
+			wantDrift:  []string{`label "env" added`},
+			wantAdded:  []string{"01"},
+			wantMapped: []paradoxv1alpha1.InfluxID{"01"},
+		},
+		{
+			name:        "only recorded labels removed",
+			recorded:    []paradoxv1alpha1.InfluxID{"01"},
+			existing:    []string{"01", "03"},
+			wantDeleted: []string{"01"},
+			wantMapped:  []paradoxv1alpha1.InfluxID{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu      sync.Mutex
+				added   []string
+				deleted []string
+			)
+
+			server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+
+				switch r.Method {
+				case nethttp.MethodGet:
+					var body struct {
+						Labels []map[string]string `json:"labels"`
+					}
+					for _, id := range tt.existing {
+						body.Labels = append(body.Labels, map[string]string{"id": id})
+					}
+
+					_ = json.NewEncoder(w).Encode(body)
+				case nethttp.MethodPost:
+					var body map[string]string
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					added = append(added, body["labelID"])
+
+					w.WriteHeader(nethttp.StatusCreated)
+					_, _ = w.Write([]byte(`{}`))
+				case nethttp.MethodDelete:
+					deleted = append(deleted, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+
+					w.WriteHeader(nethttp.StatusNoContent)
+				}
+			}))
+			defer server.Close()
+
+			iclient := influxdb.NewClient(server.URL, "secret")
+			defer iclient.Close()
+
+			mappings := newLabelMappings(nil)
+			if tt.recorded != nil {
+				mappings.set(instance, tt.recorded)
+			}
+
+			drift, err := tt.labels.syncLabelMappings(context.Background(), iclient.HTTPService(), instance, "buckets", "0a0b0c0d0e0f0001", mappings)
+			if err != nil {
+				t.Fatalf("syncLabelMappings() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(drift, tt.wantDrift) {
+				t.Errorf("drift = %q, want %q", drift, tt.wantDrift)
+			}
+
+			if !reflect.DeepEqual(added, tt.wantAdded) || !reflect.DeepEqual(deleted, tt.wantDeleted) {
+				t.Errorf("added %v and deleted %v, want %v and %v", added, deleted, tt.wantAdded, tt.wantDeleted)
+			}
+
+			if got := mappings.get(instance); !reflect.DeepEqual(got, tt.wantMapped) {
+				t.Errorf("mappings = %v, want %v", got, tt.wantMapped)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"sort"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/http"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// measurementSchema is the Influx representation of a measurement schema.
+// The measurement schema API is not covered by the Influx client, and so
+// is called directly through its HTTP service.
+type measurementSchema struct {
+	ID      string                                    `json:"id,omitempty"`
+	Name    string                                    `json:"name,omitempty"`
+	Columns []paradoxv1alpha1.MeasurementSchemaColumn `json:"columns"`
+}
+
+type measurementSchemaList struct {
+	MeasurementSchemas []measurementSchema `json:"measurementSchemas"`
+}
+
+// measurementSchemaURL returns the URL of the measurement schemas of bucketID,
+// or of the single schema identified by schemaID when supplied.
+func measurementSchemaURL(service http.Service, orgID, bucketID string, schemaID ...string) string {
+	path := service.ServerAPIURL() + "buckets/" + url.PathEscape(bucketID) + "/schema/measurements"
+	for _, id := range schemaID {
+		path += "/" + url.PathEscape(id)
+	}
+
+	return path + "?" + url.Values{"orgID": []string{orgID}}.Encode()
+}
+
+// reconcileMeasurementSchemas creates each desired measurement schema missing
+// from the bucket identified by bucketID, and adds any new columns to those which
+// exist. Changes which Influx cannot apply, such as removing a measurement or
+// removing or retyping a column, are returned as incompatibilities.
+func reconcileMeasurementSchemas(ctx context.Context, client influxdb.Client, orgID, bucketID string, desired []paradoxv1alpha1.MeasurementSchema) (incompatible []string, err error) {
+	service := client.HTTPService()
+
+	var list measurementSchemaList
+	if err := doInfluxRequest(ctx, service, nethttp.MethodGet, measurementSchemaURL(service, orgID, bucketID), nil, &list); err != nil {
+		return nil, fmt.Errorf("listing measurement schemas: %w", err)
+	}
+
+	existing := make(map[string]measurementSchema, len(list.MeasurementSchemas))
+	for _, schema := range list.MeasurementSchemas {
+		existing[schema.Name] = schema
+	}
+
+	for _, schema := range desired {
+		current, ok := existing[schema.Name]
+		if !ok {
+			if err := doInfluxRequest(ctx, service, nethttp.MethodPost, measurementSchemaURL(service, orgID, bucketID), measurementSchema{
+				Name:    schema.Name,
+				Columns: schema.Columns,
+			}, nil); err != nil {
+				return nil, fmt.Errorf("creating measurement schema %q: %w", schema.Name, err)
+			}
+
+			continue
+		}
+
+		delete(existing, schema.Name)
+
+		columns, changes := mergeMeasurementColumns(current.Columns, schema.Columns)
+		for _, change := range changes {
+			incompatible = append(incompatible, fmt.Sprintf("measurement %q: %s", schema.Name, change))
+		}
+
+		if len(columns) == len(current.Columns) {
+			continue
+		}
+
+		if err := doInfluxRequest(ctx, service, nethttp.MethodPatch, measurementSchemaURL(service, orgID, bucketID, current.ID), measurementSchema{
+			Columns: columns,
+		}, nil); err != nil {
+			return nil, fmt.Errorf("updating measurement schema %q: %w", schema.Name, err)
+		}
+	}
+
+	for name := range existing {
+		incompatible = append(incompatible, fmt.Sprintf("measurement %q cannot be removed", name))
+	}
+
+	sort.Strings(incompatible)
+
+	return incompatible, nil
+}
+
+// mergeMeasurementColumns returns the existing columns followed by each desired
+// column they lack, along with a description of every existing column which the
+// desired columns remove or change.
+func mergeMeasurementColumns(existing, desired []paradoxv1alpha1.MeasurementSchemaColumn) (columns []paradoxv1alpha1.MeasurementSchemaColumn, incompatible []string) {
+	declared := make(map[string]paradoxv1alpha1.MeasurementSchemaColumn, len(desired))
+	for _, column := range desired {
+		declared[column.Name] = column
+	}
+
+	columns = append(columns, existing...)
+
+	for _, column := range existing {
+		want, ok := declared[column.Name]
+		if !ok {
+			incompatible = append(incompatible, fmt.Sprintf("column %q cannot be removed", column.Name))
+
+			continue
+		}
+
+		delete(declared, column.Name)
+
+		if want.Type != column.Type || want.DataType != column.DataType {
+			incompatible = append(incompatible, fmt.Sprintf("column %q cannot change from %s to %s",
+				column.Name, formatColumnType(column), formatColumnType(want)))
+		}
+	}
+
+	for _, column := range desired {
+		if _, ok := declared[column.Name]; ok {
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, incompatible
+}
+
+// formatColumnType describes the semantic and data type of column.
+func formatColumnType(column paradoxv1alpha1.MeasurementSchemaColumn) string {
+	if column.DataType == "" {
+		return string(column.Type)
+	}
+
+	return fmt.Sprintf("%s(%s)", column.Type, column.DataType)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+func TestMergeMeasurementColumns(t *testing.T) {
+	column := func(name string, semantic paradoxv1alpha1.ColumnSemanticType, data paradoxv1alpha1.ColumnDataType) paradoxv1alpha1.MeasurementSchemaColumn {
+		return paradoxv1alpha1.MeasurementSchemaColumn{Name: name, Type: semantic, DataType: data}
+	}
+
+	var (
+		timestamp = column("time", paradoxv1alpha1.ColumnSemanticTypeTimestamp, "")
+		host      = column("host", paradoxv1alpha1.ColumnSemanticTypeTag, "")
+		usage     = column("usage", paradoxv1alpha1.ColumnSemanticTypeField, "float")
+		count     = column("count", paradoxv1alpha1.ColumnSemanticTypeField, "integer")
+	)
+
+	tests := []struct {
+		name             string
+		existing         []paradoxv1alpha1.MeasurementSchemaColumn
+		desired          []paradoxv1alpha1.MeasurementSchemaColumn
+		wantColumns      []paradoxv1alpha1.MeasurementSchemaColumn
+		wantIncompatible []string
+	}{
+		{
+			name:        "unchanged",
+			existing:    []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, host, usage},
+			desired:     []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, host, usage},
+			wantColumns: []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, host, usage},
+		},
+		{
+			name:        "reordered",
+			existing:    []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, host, usage},
+			desired:     []paradoxv1alpha1.MeasurementSchemaColumn{usage, timestamp, host},
+			wantColumns: []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, host, usage},
+		},
+		{
+			name:        "columns added after existing columns",
+			existing:    []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, usage},
+			desired:     []paradoxv1alpha1.MeasurementSchemaColumn{count, timestamp, host, usage},
+			wantColumns: []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, usage, count, host},
+		},
+		{
+			name:             "column removed",
+			existing:         []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, host, usage},
+			desired:          []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, usage},
+			wantColumns:      []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, host, usage},
+			wantIncompatible: []string{`column "host" cannot be removed`},
+		},
+		{
+			name:             "data type changed",
+			existing:         []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, usage},
+			desired:          []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, column("usage", paradoxv1alpha1.ColumnSemanticTypeField, "integer")},
+			wantColumns:      []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, usage},
+			wantIncompatible: []string{`column "usage" cannot change from field(float) to field(integer)`},
+		},
+		{
+			name:             "semantic type changed",
+			existing:         []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, host},
+			desired:          []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, column("host", paradoxv1alpha1.ColumnSemanticTypeField, "string")},
+			wantColumns:      []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, host},
+			wantIncompatible: []string{`column "host" cannot change from tag to field(string)`},
+		},
+		{
+			name:        "new measurement",
+			desired:     []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, host},
+			wantColumns: []paradoxv1alpha1.MeasurementSchemaColumn{timestamp, host},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, incompatible := mergeMeasurementColumns(tt.existing, tt.desired)
+			if !reflect.DeepEqual(columns, tt.wantColumns) {
+				t.Errorf("mergeMeasurementColumns() columns = %v, want %v", columns, tt.wantColumns)
+			}
+
+			if !reflect.DeepEqual(incompatible, tt.wantIncompatible) {
+				t.Errorf("mergeMeasurementColumns() incompatible = %q, want %q", incompatible, tt.wantIncompatible)
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// notificationEndpointSecretKeys are the fields of a notification endpoint
+// which hold secrets. Influx replaces them with references to its own secret
+// store, so they cannot be compared with the spec.
+var notificationEndpointSecretKeys = []string{"token", "username", "password", "routingKey"}
+
+// NotificationEndpointReconciler reconciles a NotificationEndpoint object
+type NotificationEndpointReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Clients is the pool of Influx clients shared by every reconciler.
+	Clients *ClientPool
+	// ResyncInterval is the interval at which each notification endpoint is
+	// reconciled against its target instances, unless overridden by annotation.
+	ResyncInterval time.Duration
+}
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=notificationendpoints,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=notificationendpoints/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=notificationendpoints/finalizers,verbs=update
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations/status,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=labels,verbs=get;list;watch
+
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// Notification endpoints which already exist are compared against the spec and
+// any drift is corrected. As Influx does not return secrets, endpoints are
+// rewritten whenever the content of the referenced Secrets changes.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *NotificationEndpointReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var endpoint paradoxv1alpha1.NotificationEndpoint
+	if err := r.Get(ctx, req.NamespacedName, &endpoint); err != nil {
+		log.Error(err, "unable to fetch notification endpoint")
+
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	log = log.WithValues("notificationEndpoint", endpoint)
+
+	if !endpoint.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := finalize(ctx, r.Client, &endpoint, endpoint.Spec.DeletionPolicy, func() error {
+			return r.deleteInstanceEndpoints(ctx, &endpoint)
+		}); err != nil {
+			log.Error(err, "failed to finalize notification endpoint")
+
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if err := syncFinalizer(ctx, r.Client, &endpoint, endpoint.Spec.DeletionPolicy); err != nil {
+		log.Error(err, "failed to update finalizers")
+
+		return ctrl.Result{}, err
+	}
+
+	var organization paradoxv1alpha1.Organization
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: req.NamespacedName.Namespace,
+		Name:      endpoint.Spec.Organization,
+	}, &organization); err != nil {
+		log.Error(err, "unable to fetch organization")
+
+		return ctrl.Result{}, client.IgnoreNotFound(r.updateStatus(ctx, &endpoint, endpoint.Status.Instances, endpoint.Status.SecretsHashes, endpoint.Status.LabelMappings, fmt.Errorf("organization %q: %w", endpoint.Spec.Organization, err)))
+	}
+
+	desired, secretsHash, err := r.influxNotificationEndpoint(ctx, endpoint.Spec)
+	if err != nil {
+		log.Error(err, "unable to resolve notification endpoint secrets")
+
+		return ctrl.Result{}, r.updateStatus(ctx, &endpoint, endpoint.Status.Instances, endpoint.Status.SecretsHashes, endpoint.Status.LabelMappings, err)
+	}
+
+	labels, err := fetchLabels(ctx, r.Client, req.NamespacedName.Namespace, endpoint.Spec.Organization, endpoint.Spec.Labels)
+	if err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, &endpoint, endpoint.Status.Instances, endpoint.Status.SecretsHashes, endpoint.Status.LabelMappings, err)
+	}
+
+	var (
+		mu       sync.Mutex
+		drift    []string
+		hashes   = endpoint.Status.SecretsHashes.DeepCopy()
+		mappings = newLabelMappings(endpoint.Status.LabelMappings)
+	)
+
+	if hashes == nil {
+		hashes = paradoxv1alpha1.InstanceHashes{}
+	}
+
+	instances, err := reconcileInstances(ctx, r.Client, r.Clients, &organization, endpoint.Status.Instances, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) (*paradoxv1alpha1.InfluxID, error) {
+		namespace, name := instance.ObjectMeta.Namespace, instance.ObjectMeta.Name
+
+		orgID, err := organizationID(&organization, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		previous := endpoint.Status.Instances[namespace][name].ID
+
+		// the secrets are rewritten to each instance whenever they differ from
+		// those last written to it
+		mu.Lock()
+		force := hashes.Get(namespace, name) != secretsHash
+		mu.Unlock()
+
+		id, changes, err := notificationEndpointsResource.sync(ctx, client.HTTPService(), previous, orgID, desired, force, notificationEndpointSecretKeys...)
+		if err != nil {
+			return id, err
+		}
+
+		mu.Lock()
+		hashes.Set(namespace, name, secretsHash)
+		mu.Unlock()
+
+		labelDrift, err := labels.syncLabelMappings(ctx, client.HTTPService(), instance, notificationEndpointsResource.path, *id, mappings)
+		if err != nil {
+			return id, err
+		}
+
+		changes = append(changes, labelDrift...)
+
+		if len(changes) > 0 {
+			message := fmt.Sprintf("corrected drift in instance %s/%s: %s", namespace, name, strings.Join(changes, ", "))
+			r.Recorder.Event(&endpoint, corev1.EventTypeNormal, "DriftCorrected", message)
+
+			mu.Lock()
+			drift = append(drift, message)
+			mu.Unlock()
+		}
+
+		return id, nil
+	})
+	if err != nil {
+		log.Error(err, "error while configuring instances")
+	}
+
+	setDriftCondition(&endpoint.Status.Conditions, endpoint.Generation, drift)
+
+	return resyncResult(ctx, &endpoint, r.ResyncInterval), r.updateStatus(ctx, &endpoint, instances, hashes, mappings.assigned, err)
+}
+
+// updateStatus records instances, secrets hashes and label mappings along with
+// the conditions derived from reconcileErr in the status of endpoint. The reconcile
+// error is returned unless the status update itself fails.
+func (r *NotificationEndpointReconciler) updateStatus(ctx context.Context, endpoint *paradoxv1alpha1.NotificationEndpoint, instances paradoxv1alpha1.Instances, hashes paradoxv1alpha1.InstanceHashes, mappings paradoxv1alpha1.InstanceLabels, reconcileErr error) error {
+	if instances == nil {
+		instances = paradoxv1alpha1.Instances{}
+	}
+
+	// hashes are only kept for instances which are still recorded
+	hashes.Retain(instances)
+
+	// label mappings are only kept for instances which are still recorded
+	mappings.Retain(instances)
+
+	endpoint.Status.ObservedGeneration = endpoint.Generation
+	endpoint.Status.Instances = instances
+	endpoint.Status.SecretsHashes = hashes
+	endpoint.Status.LabelMappings = mappings
+
+	setConditions(&endpoint.Status.Conditions, endpoint.Generation, instances, reconcileErr)
+
+	if err := r.Status().Update(ctx, endpoint); err != nil {
+		log.FromContext(ctx).Error(err, "failed to update status")
+
+		return err
+	}
+
+	return reconcileErr
+}
+
+// deleteInstanceEndpoints removes the notification endpoint from every target
+// instance in which it has previously been recorded.
+func (r *NotificationEndpointReconciler) deleteInstanceEndpoints(ctx context.Context, endpoint *paradoxv1alpha1.NotificationEndpoint) error {
+	deletion := instanceDeletion{
+		Client:    r.Client,
+		Clients:   r.Clients,
+		Recorder:  r.Recorder,
+		Object:    endpoint,
+		Noun:      "notification endpoint",
+		Instances: endpoint.Status.Instances,
+	}
+
+	return deletion.deleteWithin(ctx, endpoint.Spec.Organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client, id paradoxv1alpha1.InfluxID) error {
+		return notificationEndpointsResource.delete(ctx, client.HTTPService(), id)
+	})
+}
+
+// influxNotificationEndpoint returns the Influx representation of the notification
+// endpoint defined by spec, with its secrets resolved, along with a hash of the secrets.
+func (r *NotificationEndpointReconciler) influxNotificationEndpoint(ctx context.Context, spec paradoxv1alpha1.NotificationEndpointSpec) (influxObject, string, error) {
+	hash := sha256.New()
+
+	secret := func(ref *paradoxv1alpha1.SecretRef) (string, error) {
+		if ref == nil {
+			return "", nil
+		}
+
+		value, err := resolveSecretKey(ctx, r.Client, *ref)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(hash, "%s/%s/%s=%s\n", ref.Namespace, ref.Name, ref.Key, value)
+
+		return value, nil
+	}
+
+	endpoint := influxObject{
+		"name":        spec.Name,
+		"description": spec.Description,
+		"status":      string(activityStatus(spec.Status)),
+		"type":        string(spec.Type()),
+	}
+
+	switch {
+	case spec.Slack != nil:
+		token, err := secret(spec.Slack.TokenSecretRef)
+		if err != nil {
+			return nil, "", err
+		}
+
+		endpoint["url"] = spec.Slack.URL
+		endpoint["token"] = token
+	case spec.HTTP != nil:
+		method, authMethod := spec.HTTP.Method, spec.HTTP.AuthMethod
+		if method == "" {
+			method = "POST"
+		}
+
+		if authMethod == "" {
+			authMethod = paradoxv1alpha1.HTTPAuthMethodNone
+		}
+
+		endpoint["url"] = spec.HTTP.URL
+		endpoint["method"] = method
+		endpoint["authMethod"] = string(authMethod)
+		endpoint["headers"] = spec.HTTP.Headers
+
+		// secrets are resolved in a fixed order, so that their hash is stable
+		for _, field := range []struct {
+			key string
+			ref *paradoxv1alpha1.SecretRef
+		}{
+			{"username", spec.HTTP.UsernameSecretRef},
+			{"password", spec.HTTP.PasswordSecretRef},
+			{"token", spec.HTTP.TokenSecretRef},
+		} {
+			value, err := secret(field.ref)
+			if err != nil {
+				return nil, "", err
+			}
+
+			if value != "" {
+				endpoint[field.key] = value
+			}
+		}
+	case spec.PagerDuty != nil:
+		routingKey, err := secret(&spec.PagerDuty.RoutingKeySecretRef)
+		if err != nil {
+			return nil, "", err
+		}
+
+		endpoint["clientURL"] = spec.PagerDuty.ClientURL
+		endpoint["routingKey"] = routingKey
+	default:
+		return nil, "", fmt.Errorf("notification endpoint %q declares no endpoint type", spec.Name)
+	}
+
+	return endpoint, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NotificationEndpointReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.NotificationEndpoint{}, orgField, func(rawObj client.Object) []string {
+		endpoint := rawObj.(*paradoxv1alpha1.NotificationEndpoint)
+		if endpoint.Spec.Organization == "" {
+			return nil
+		}
+
+		return []string{endpoint.Spec.Organization}
+	}); err != nil {
+		return err
+	}
+
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.NotificationEndpoint{}, secretField, func(rawObj client.Object) []string {
+		endpoint := rawObj.(*paradoxv1alpha1.NotificationEndpoint)
+
+		var keys []string
+		for _, ref := range endpoint.Spec.SecretRefs() {
+			keys = append(keys, ref.Namespace+"/"+ref.Name)
+		}
+
+		return keys
+	}); err != nil {
+		return err
+	}
+
+	if err := indexLabels(mgr, &paradoxv1alpha1.NotificationEndpoint{}, func(rawObj client.Object) []string {
+		return rawObj.(*paradoxv1alpha1.NotificationEndpoint).Spec.Labels
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&paradoxv1alpha1.NotificationEndpoint{}, builder.WithPredicates(specOrAnnotationChanged())).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Organization{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.NotificationEndpointList{}, orgField)),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(findForReference(r.Client, &paradoxv1alpha1.NotificationEndpointList{}, secretField)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Label{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.NotificationEndpointList{}, labelsField)),
+		).
+		Complete(r)
+}
@@ -0,0 +1,365 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+const (
+	// endpointField indexes notification rules by the name of their notification endpoint.
+	endpointField = ".spec.endpoint"
+	// checkField indexes notification rules by the name of the check they are restricted to.
+	checkField = ".spec.check"
+)
+
+var ErrReferenceNotCreated = errors.New("referenced resource has not been created in the target instance")
+
+// NotificationRuleReconciler reconciles a NotificationRule object
+type NotificationRuleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Clients is the pool of Influx clients shared by every reconciler.
+	Clients *ClientPool
+	// ResyncInterval is the interval at which each notification rule is
+	// reconciled against its target instances, unless overridden by annotation.
+	ResyncInterval time.Duration
+}
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=notificationrules,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=notificationrules/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=notificationrules/finalizers,verbs=update
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations/status,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=labels,verbs=get;list;watch
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=notificationendpoints,verbs=get;list;watch
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=checks,verbs=get;list;watch
+
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// The notification endpoint, and optional check, of the rule are resolved to
+// their identifiers within each target instance. Notification rules which
+// already exist are compared against the spec and any drift is corrected.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *NotificationRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var rule paradoxv1alpha1.NotificationRule
+	if err := r.Get(ctx, req.NamespacedName, &rule); err != nil {
+		log.Error(err, "unable to fetch notification rule")
+
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	log = log.WithValues("notificationRule", rule)
+
+	if !rule.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := finalize(ctx, r.Client, &rule, rule.Spec.DeletionPolicy, func() error {
+			return r.deleteInstanceRules(ctx, &rule)
+		}); err != nil {
+			log.Error(err, "failed to finalize notification rule")
+
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if err := syncFinalizer(ctx, r.Client, &rule, rule.Spec.DeletionPolicy); err != nil {
+		log.Error(err, "failed to update finalizers")
+
+		return ctrl.Result{}, err
+	}
+
+	var organization paradoxv1alpha1.Organization
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: req.NamespacedName.Namespace,
+		Name:      rule.Spec.Organization,
+	}, &organization); err != nil {
+		log.Error(err, "unable to fetch organization")
+
+		return ctrl.Result{}, client.IgnoreNotFound(r.updateStatus(ctx, &rule, rule.Status.Instances, rule.Status.LabelMappings, fmt.Errorf("organization %q: %w", rule.Spec.Organization, err)))
+	}
+
+	var endpoint paradoxv1alpha1.NotificationEndpoint
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: req.NamespacedName.Namespace,
+		Name:      rule.Spec.Endpoint,
+	}, &endpoint); err != nil {
+		log.Error(err, "unable to fetch notification endpoint")
+
+		return ctrl.Result{}, client.IgnoreNotFound(r.updateStatus(ctx, &rule, rule.Status.Instances, rule.Status.LabelMappings, fmt.Errorf("notification endpoint %q: %w", rule.Spec.Endpoint, err)))
+	}
+
+	var check *paradoxv1alpha1.Check
+	if rule.Spec.Check != "" {
+		check = &paradoxv1alpha1.Check{}
+		if err := r.Get(ctx, types.NamespacedName{
+			Namespace: req.NamespacedName.Namespace,
+			Name:      rule.Spec.Check,
+		}, check); err != nil {
+			log.Error(err, "unable to fetch check")
+
+			return ctrl.Result{}, client.IgnoreNotFound(r.updateStatus(ctx, &rule, rule.Status.Instances, rule.Status.LabelMappings, fmt.Errorf("check %q: %w", rule.Spec.Check, err)))
+		}
+	}
+
+	labels, err := fetchLabels(ctx, r.Client, req.NamespacedName.Namespace, rule.Spec.Organization, rule.Spec.Labels)
+	if err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, &rule, rule.Status.Instances, rule.Status.LabelMappings, err)
+	}
+
+	var (
+		mu       sync.Mutex
+		drift    []string
+		mappings = newLabelMappings(rule.Status.LabelMappings)
+	)
+
+	instances, err := reconcileInstances(ctx, r.Client, r.Clients, &organization, rule.Status.Instances, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) (*paradoxv1alpha1.InfluxID, error) {
+		namespace, name := instance.ObjectMeta.Namespace, instance.ObjectMeta.Name
+
+		orgID, err := organizationID(&organization, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		endpointID := endpoint.Status.Instances[namespace][name].ID
+		if endpointID == nil {
+			return nil, fmt.Errorf("notification endpoint %q: %w", rule.Spec.Endpoint, ErrReferenceNotCreated)
+		}
+
+		var checkID *paradoxv1alpha1.InfluxID
+		if check != nil {
+			if checkID = check.Status.Instances[namespace][name].ID; checkID == nil {
+				return nil, fmt.Errorf("check %q: %w", rule.Spec.Check, ErrReferenceNotCreated)
+			}
+		}
+
+		desired := influxNotificationRule(rule.Spec, endpoint.Spec.Type(), *endpointID, checkID)
+
+		previous := rule.Status.Instances[namespace][name].ID
+
+		id, changes, err := notificationRulesResource.sync(ctx, client.HTTPService(), previous, orgID, desired, false)
+		if err != nil {
+			return id, err
+		}
+
+		labelDrift, err := labels.syncLabelMappings(ctx, client.HTTPService(), instance, notificationRulesResource.path, *id, mappings)
+		if err != nil {
+			return id, err
+		}
+
+		changes = append(changes, labelDrift...)
+
+		if len(changes) > 0 {
+			message := fmt.Sprintf("corrected drift in instance %s/%s: %s", namespace, name, strings.Join(changes, ", "))
+			r.Recorder.Event(&rule, corev1.EventTypeNormal, "DriftCorrected", message)
+
+			mu.Lock()
+			drift = append(drift, message)
+			mu.Unlock()
+		}
+
+		return id, nil
+	})
+	if err != nil {
+		log.Error(err, "error while configuring instances")
+	}
+
+	setDriftCondition(&rule.Status.Conditions, rule.Generation, drift)
+
+	return resyncResult(ctx, &rule, r.ResyncInterval), r.updateStatus(ctx, &rule, instances, mappings.assigned, err)
+}
+
+// updateStatus records instances and label mappings along with the conditions
+// derived from reconcileErr in the status of rule. The reconcile error is returned
+// unless the status update itself fails.
+func (r *NotificationRuleReconciler) updateStatus(ctx context.Context, rule *paradoxv1alpha1.NotificationRule, instances paradoxv1alpha1.Instances, mappings paradoxv1alpha1.InstanceLabels, reconcileErr error) error {
+	if instances == nil {
+		instances = paradoxv1alpha1.Instances{}
+	}
+
+	// label mappings are only kept for instances which are still recorded
+	mappings.Retain(instances)
+
+	rule.Status.ObservedGeneration = rule.Generation
+	rule.Status.Instances = instances
+	rule.Status.LabelMappings = mappings
+	setConditions(&rule.Status.Conditions, rule.Generation, instances, reconcileErr)
+
+	if err := r.Status().Update(ctx, rule); err != nil {
+		log.FromContext(ctx).Error(err, "failed to update status")
+
+		return err
+	}
+
+	return reconcileErr
+}
+
+// deleteInstanceRules removes the notification rule from every target instance
+// in which it has previously been recorded.
+func (r *NotificationRuleReconciler) deleteInstanceRules(ctx context.Context, rule *paradoxv1alpha1.NotificationRule) error {
+	deletion := instanceDeletion{
+		Client:    r.Client,
+		Clients:   r.Clients,
+		Recorder:  r.Recorder,
+		Object:    rule,
+		Noun:      "notification rule",
+		Instances: rule.Status.Instances,
+	}
+
+	return deletion.deleteWithin(ctx, rule.Spec.Organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client, id paradoxv1alpha1.InfluxID) error {
+		return notificationRulesResource.delete(ctx, client.HTTPService(), id)
+	})
+}
+
+// influxNotificationRule returns the Influx representation of the notification rule
+// defined by spec, which notifies the endpoint identified by endpointID. When checkID
+// is set the rule is restricted to the statuses written by that check.
+func influxNotificationRule(
+	spec paradoxv1alpha1.NotificationRuleSpec,
+	endpointType paradoxv1alpha1.NotificationEndpointType,
+	endpointID paradoxv1alpha1.InfluxID,
+	checkID *paradoxv1alpha1.InfluxID,
+) influxObject {
+	statusRules := make([]influxObject, len(spec.StatusRules))
+	for i, statusRule := range spec.StatusRules {
+		statusRules[i] = influxObject{
+			"currentLevel":  string(statusRule.CurrentLevel),
+			"previousLevel": string(statusRule.PreviousLevel),
+		}
+	}
+
+	tagRules := make([]influxObject, 0, len(spec.TagRules)+1)
+	for _, tagRule := range spec.TagRules {
+		operator := tagRule.Operator
+		if operator == "" {
+			operator = paradoxv1alpha1.TagRuleOperatorEqual
+		}
+
+		tagRules = append(tagRules, influxObject{
+			"key":      tagRule.Key,
+			"value":    tagRule.Value,
+			"operator": string(operator),
+		})
+	}
+
+	// statuses are tagged with the identifier of the check which wrote them
+	if checkID != nil {
+		tagRules = append(tagRules, influxObject{
+			"key":      "_check_id",
+			"value":    string(*checkID),
+			"operator": string(paradoxv1alpha1.TagRuleOperatorEqual),
+		})
+	}
+
+	rule := influxObject{
+		"name":        spec.Name,
+		"description": spec.Description,
+		"status":      string(activityStatus(spec.Status)),
+		"type":        string(endpointType),
+		"endpointID":  string(endpointID),
+		"every":       spec.Every,
+		"offset":      spec.Offset,
+		"statusRules": statusRules,
+		"tagRules":    tagRules,
+	}
+
+	switch endpointType {
+	case paradoxv1alpha1.NotificationEndpointTypeSlack:
+		rule["messageTemplate"] = spec.MessageTemplate
+		rule["channel"] = spec.Channel
+	case paradoxv1alpha1.NotificationEndpointTypePagerDuty:
+		rule["messageTemplate"] = spec.MessageTemplate
+	}
+
+	return rule
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NotificationRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	for field, value := range map[string]func(*paradoxv1alpha1.NotificationRule) string{
+		orgField:      func(rule *paradoxv1alpha1.NotificationRule) string { return rule.Spec.Organization },
+		endpointField: func(rule *paradoxv1alpha1.NotificationRule) string { return rule.Spec.Endpoint },
+		checkField:    func(rule *paradoxv1alpha1.NotificationRule) string { return rule.Spec.Check },
+	} {
+		value := value
+		if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.NotificationRule{}, field, func(rawObj client.Object) []string {
+			if name := value(rawObj.(*paradoxv1alpha1.NotificationRule)); name != "" {
+				return []string{name}
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := indexLabels(mgr, &paradoxv1alpha1.NotificationRule{}, func(rawObj client.Object) []string {
+		return rawObj.(*paradoxv1alpha1.NotificationRule).Spec.Labels
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&paradoxv1alpha1.NotificationRule{}, builder.WithPredicates(specOrAnnotationChanged())).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Organization{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.NotificationRuleList{}, orgField)),
+		).
+		// the identifiers of endpoints and checks are recorded in their status,
+		// so rules follow every change to them rather than only their spec
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.NotificationEndpoint{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.NotificationRuleList{}, endpointField)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Check{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.NotificationRuleList{}, checkField)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Label{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.NotificationRuleList{}, labelsField)),
+		).
+		Complete(r)
+}
@@ -20,24 +20,76 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	nethttp "net/http"
+	"strings"
+	"sync"
+	"time"
 
 	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/http"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
 )
 
+const (
+	// instanceField indexes organizations by the namespace/name of each instance they reference.
+	instanceField = ".spec.instance_refs"
+	// secretField indexes resources by the namespace/name of each Secret holding their credentials.
+	secretField = ".spec.secretRef"
+
+	// maxConcurrentInstances bounds the number of target instances
+	// which are configured concurrently for a single resource.
+	maxConcurrentInstances = 8
+)
+
 var (
 	ErrOrgHasNoAuthorization = errors.New("organization has no associated admin authorization token")
 
+	ErrInstanceHasNoAuthorization = errors.New("instance has no associated operator authorization token")
+
 	ErrInfluxUnexpectedResponse = errors.New("target Influx instance returned unexpected response")
+
+	ErrOrgNotCreated = errors.New("organization has not been created in the target instance")
+
+	ErrInstanceNotFound = errors.New("instance no longer exists")
 )
 
+// isInfluxNotFound returns true when err signifies that the requested
+// resource does not exist within the target Influx instance.
+func isInfluxNotFound(err error) bool {
+	var herr *http.Error
+	if errors.As(err, &herr) {
+		return herr.StatusCode == nethttp.StatusNotFound
+	}
+
+	return strings.Contains(err.Error(), "not found")
+}
+
+// organizationID returns the identifier of organization within instance.
+func organizationID(organization *paradoxv1alpha1.Organization, instance *paradoxv1alpha1.Instance) (string, error) {
+	id := organization.Status.Instances[instance.ObjectMeta.Namespace][instance.ObjectMeta.Name].ID
+	if id == nil {
+		return "", fmt.Errorf("organization %q: %w", organization.Spec.Name, ErrOrgNotCreated)
+	}
+
+	return string(*id), nil
+}
+
 func toStringPtr[V ~string](v *V) *string {
 	if v == nil {
 		return nil
@@ -56,17 +108,38 @@ func fromStringPtr[V ~string](str *string) *V {
 	return &v
 }
 
+func fromPtr[V any](v *V) (zero V) {
+	if v == nil {
+		return zero
+	}
+
+	return *v
+}
+
 // OrganizationReconciler reconciles a Organization object
 type OrganizationReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Clients is the pool of Influx clients shared by every reconciler.
+	Clients *ClientPool
+
+	// ResyncInterval is the interval at which each organization is reconciled
+	// against its target instances, unless overridden by annotation.
+	ResyncInterval time.Duration
 }
 
 //+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations/finalizers,verbs=update
 
-//+kubebuilder:rbac:groups=apps,resources=secrets,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=instances,verbs=get;list;watch
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=users,verbs=get;list;watch
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=users/status,verbs=get
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -78,120 +151,614 @@ func (r *OrganizationReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	var organization paradoxv1alpha1.Organization
 	if err := r.Get(ctx, req.NamespacedName, &organization); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Clients.CloseOrganization(req.NamespacedName)
+
+			return ctrl.Result{}, nil
+		}
+
 		log.Error(err, "unable to fetch organization")
 
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		return ctrl.Result{}, err
 	}
 
 	log = log.WithValues("organization", organization)
 
-	status := paradoxv1alpha1.OrganizationStatus{
-		Instances: paradoxv1alpha1.Instances{},
+	if !organization.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := finalize(ctx, r.Client, &organization, organization.Spec.DeletionPolicy, func() error {
+			return r.deleteInstanceOrganizations(ctx, &organization)
+		}); err != nil {
+			log.Error(err, "failed to finalize organization")
+
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
 	}
 
-	if err := forEachInstanceClient(ctx, r.Client, &organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) error {
+	if err := syncFinalizer(ctx, r.Client, &organization, organization.Spec.DeletionPolicy); err != nil {
+		log.Error(err, "failed to update finalizers")
+
+		return ctrl.Result{}, err
+	}
+
+	users, err := fetchUsers(ctx, r.Client, organization.ObjectMeta.Namespace)
+	if err != nil {
+		log.Error(err, "unable to list users")
+
+		return ctrl.Result{}, err
+	}
+
+	var (
+		mu      sync.Mutex
+		created = organization.Status.CreatedIDs.DeepCopy()
+	)
+
+	if created == nil {
+		created = paradoxv1alpha1.InstanceIDs{}
+	}
+
+	instances, err := reconcileInstances(ctx, r.Client, r.Clients, &organization, organization.Status.Instances, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) (*paradoxv1alpha1.InfluxID, error) {
+		namespace, name := instance.ObjectMeta.Namespace, instance.ObjectMeta.Name
+
 		orgAPI := client.OrganizationsAPI()
 		org, err := orgAPI.FindOrganizationByName(ctx, organization.Spec.Name)
 		if err != nil {
-			log.Error(err, "could not fetch from Influx instance")
+			if !isInfluxNotFound(err) {
+				log.Error(err, "could not fetch from Influx instance")
 
-			// TODO(georgemac): in the future add support for org creation by way of instance
-			// provisioning credentials
-			return err
-		}
+				return nil, err
+			}
+
+			// create organization by way of instance provisioning credentials
+			org, err = r.createInstanceOrganization(ctx, instance, &organization)
+			if err != nil {
+				log.Error(err, "could not create organization in Influx instance")
 
-		// update target org description if they differ
-		if org.Description != nil && *org.Description != organization.Spec.Description {
+				return nil, err
+			}
+
+			if org.Id != nil {
+				mu.Lock()
+				created.Set(namespace, name, paradoxv1alpha1.InfluxID(*org.Id))
+				mu.Unlock()
+			}
+		} else if org.Description != nil && *org.Description != organization.Spec.Description {
+			// update target org description if they differ
 			org.Description = &organization.Spec.Description
 			org, err = orgAPI.UpdateOrganization(ctx, org)
 			if err != nil {
 				log.Error(err, "could not update target Influx instance")
 
-				return err
+				return nil, err
 			}
 		}
 
-		status.Instances.AddInstance(
-			instance,
-			fromStringPtr[paradoxv1alpha1.InfluxID](org.Id),
-		)
+		id := fromStringPtr[paradoxv1alpha1.InfluxID](org.Id)
+		if id == nil {
+			return nil, fmt.Errorf("organization %q: %w", organization.Spec.Name, ErrInfluxUnexpectedResponse)
+		}
 
-		return nil
-	}); err != nil {
+		mu.Lock()
+		adopted := *id != created.Get(namespace, name)
+		mu.Unlock()
+
+		// an organization which already existed is adopted, and so is
+		// retained upon deletion
+		if adopted && organization.Status.Instances[namespace][name].ID == nil {
+			r.Recorder.Eventf(&organization, corev1.EventTypeNormal, "Adopted",
+				"adopted existing organization %s in instance %s/%s, which is retained upon deletion", *id, namespace, name)
+		}
+
+		changes, err := syncUsers(ctx, orgAPI, instance, string(*id), users, organization.Spec)
+		if len(changes) > 0 {
+			log.Info("updated organization users", "instance", instance.ObjectMeta.Namespace+"/"+instance.ObjectMeta.Name, "changes", changes)
+		}
+
+		return id, err
+	})
+	if err != nil {
 		log.Error(err, "error while configuring instances")
+	}
 
-		return ctrl.Result{}, err
+	return resyncResult(ctx, &organization, r.ResyncInterval), r.updateStatus(ctx, &organization, instances, created, err)
+}
+
+// updateStatus records instances and the organizations created by the operator
+// along with the conditions derived from reconcileErr in the status of organization.
+// The reconcile error is returned unless the status update itself fails.
+func (r *OrganizationReconciler) updateStatus(ctx context.Context, organization *paradoxv1alpha1.Organization, instances paradoxv1alpha1.Instances, created paradoxv1alpha1.InstanceIDs, reconcileErr error) error {
+	if instances == nil {
+		instances = paradoxv1alpha1.Instances{}
 	}
 
-	organization.Status = status
+	// created organizations are only kept for instances which are still recorded
+	created.Retain(instances)
 
-	if err := r.Status().Update(ctx, &organization); err != nil {
-		log.Error(err, "failed to update status")
+	organization.Status.ObservedGeneration = organization.Generation
+	organization.Status.Instances = instances
+	organization.Status.CreatedIDs = created
+	setConditions(&organization.Status.Conditions, organization.Generation, instances, reconcileErr)
 
-		return ctrl.Result{}, err
+	if err := r.Status().Update(ctx, organization); err != nil {
+		log.FromContext(ctx).Error(err, "failed to update status")
+
+		return err
+	}
+
+	return reconcileErr
+}
+
+// createInstanceOrganization creates the organization within instance using the
+// operator credentials of the instance.
+func (r *OrganizationReconciler) createInstanceOrganization(ctx context.Context, instance *paradoxv1alpha1.Instance, organization *paradoxv1alpha1.Organization) (*domain.Organization, error) {
+	client, release, err := operatorClient(ctx, r.Client, r.Clients, instance)
+	if err != nil {
+		return nil, fmt.Errorf("creating organization: %w", err)
 	}
+	defer release()
 
-	return ctrl.Result{}, nil
+	return client.OrganizationsAPI().CreateOrganization(ctx, &domain.Organization{
+		Name:        organization.Spec.Name,
+		Description: &organization.Spec.Description,
+	})
+}
+
+// deleteInstanceOrganizations removes the organization from every target instance
+// in which it was created by the operator. Organizations which were adopted are
+// left in place.
+func (r *OrganizationReconciler) deleteInstanceOrganizations(ctx context.Context, organization *paradoxv1alpha1.Organization) error {
+	deletion := instanceDeletion{
+		Client:    r.Client,
+		Clients:   r.Clients,
+		Recorder:  r.Recorder,
+		Object:    organization,
+		Noun:      "organization",
+		Instances: createdOrganizations(organization.Status),
+	}
+
+	for namespace, namespaced := range organization.Status.Instances {
+		for name, resource := range namespaced {
+			if resource.ID != nil && deletion.Instances[namespace][name].ID == nil {
+				r.Recorder.Eventf(organization, corev1.EventTypeNormal, "Retained",
+					"organization %s retained in instance %s/%s as it was not created by the operator", *resource.ID, namespace, name)
+			}
+		}
+	}
+
+	return deletion.delete(ctx, organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client, id paradoxv1alpha1.InfluxID) error {
+		// prefer the operator credentials of the instance, as organization
+		// scoped credentials may not be permitted to delete the organization
+		if instance.Spec.Authorization != nil {
+			operator, release, err := operatorClient(ctx, r.Client, r.Clients, instance)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			client = operator
+		}
+
+		if err := client.OrganizationsAPI().DeleteOrganizationWithID(ctx, string(id)); err != nil && !isInfluxNotFound(err) {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// createdOrganizations returns the instances recorded in status in which the
+// organization was created by the operator, rather than adopted.
+func createdOrganizations(status paradoxv1alpha1.OrganizationStatus) paradoxv1alpha1.Instances {
+	instances := paradoxv1alpha1.Instances{}
+	for namespace, namespaced := range status.Instances {
+		for name, resource := range namespaced {
+			if resource.ID == nil || *resource.ID != status.CreatedIDs.Get(namespace, name) {
+				continue
+			}
+
+			if instances[namespace] == nil {
+				instances[namespace] = map[string]paradoxv1alpha1.ResourceInstance{}
+			}
+
+			instances[namespace][name] = resource
+		}
+	}
+
+	return instances
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *OrganizationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.Organization{}, instanceField, func(rawObj client.Object) []string {
+		org := rawObj.(*paradoxv1alpha1.Organization)
+
+		var keys []string
+		for namespace, refs := range org.Spec.InstanceRefs {
+			for name := range refs {
+				keys = append(keys, namespace+"/"+name)
+			}
+		}
+
+		// instances which are no longer referenced must still be removed from the status
+		for namespace, instances := range org.Status.Instances {
+			for name := range instances {
+				keys = append(keys, namespace+"/"+name)
+			}
+		}
+
+		return keys
+	}); err != nil {
+		return err
+	}
+
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.Organization{}, secretField, func(rawObj client.Object) []string {
+		org := rawObj.(*paradoxv1alpha1.Organization)
+
+		var keys []string
+		for _, refs := range org.Spec.InstanceRefs {
+			for _, auth := range refs {
+				if auth.Secret != nil {
+					keys = append(keys, auth.Secret.Namespace+"/"+auth.Secret.Name)
+				}
+			}
+		}
+
+		// selected instances are resolved to the secrets they were last reconciled with
+		if selector := org.Spec.InstanceSelector; selector != nil && selector.Authorization.Secret != nil {
+			target := instanceTarget{auth: selector.Authorization, selected: true}
+			for namespace, instances := range org.Status.Instances {
+				for name := range instances {
+					if auth, err := target.authorization(types.NamespacedName{Namespace: namespace, Name: name}); err == nil {
+						keys = append(keys, auth.Secret.Namespace+"/"+auth.Secret.Name)
+					}
+				}
+			}
+		}
+
+		return keys
+	}); err != nil {
+		return err
+	}
+
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.Instance{}, secretField, func(rawObj client.Object) []string {
+		instance := rawObj.(*paradoxv1alpha1.Instance)
+
+		var keys []string
+		if auth := instance.Spec.Authorization; auth != nil && auth.Secret != nil {
+			keys = append(keys, auth.Secret.Namespace+"/"+auth.Secret.Name)
+		}
+
+		if tls := instance.Spec.TLS; tls != nil {
+			if tls.CASecretRef != nil {
+				keys = append(keys, tls.CASecretRef.Namespace+"/"+tls.CASecretRef.Name)
+			}
+
+			if tls.ClientCertificateSecretRef != nil {
+				keys = append(keys, tls.ClientCertificateSecretRef.Namespace+"/"+tls.ClientCertificateSecretRef.Name)
+			}
+		}
+
+		return keys
+	}); err != nil {
+		return err
+	}
+
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.Organization{}, usersField, func(rawObj client.Object) []string {
+		org := rawObj.(*paradoxv1alpha1.Organization)
+
+		return append(append([]string{}, org.Spec.Members...), org.Spec.Owners...)
+	}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&paradoxv1alpha1.Organization{}).
+		For(&paradoxv1alpha1.Organization{}, builder.WithPredicates(specOrAnnotationChanged())).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Instance{}},
+			handler.EnqueueRequestsFromMapFunc(r.findOrganizationsForInstance),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.findOrganizationsForSecret),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.User{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.OrganizationList{}, usersField)),
+		).
 		Complete(r)
 }
 
+// findOrganizationsForInstance returns a request for every organization which
+// references or selects instance, or which has previously targeted it, so that
+// organizations follow instances as they are created, changed, relabelled and deleted.
+func (r *OrganizationReconciler) findOrganizationsForInstance(instance client.Object) []reconcile.Request {
+	ctx := context.Background()
+
+	requests := map[types.NamespacedName]struct{}{}
+
+	var referencing paradoxv1alpha1.OrganizationList
+	if err := r.List(ctx, &referencing, client.MatchingFields{
+		instanceField: instance.GetNamespace() + "/" + instance.GetName(),
+	}); err != nil {
+		return []reconcile.Request{}
+	}
+
+	for _, organization := range referencing.Items {
+		requests[client.ObjectKeyFromObject(&organization)] = struct{}{}
+	}
+
+	var organizations paradoxv1alpha1.OrganizationList
+	if err := r.List(ctx, &organizations); err != nil {
+		return []reconcile.Request{}
+	}
+
+	for _, organization := range organizations.Items {
+		if selected, err := selectsInstance(ctx, r.Client, &organization, instance); err == nil && selected {
+			requests[client.ObjectKeyFromObject(&organization)] = struct{}{}
+		}
+	}
+
+	return toRequests(requests)
+}
+
+// findOrganizationsForSecret returns a request for every organization which
+// authorizes requests using secret, either directly or by way of the operator
+// credential of a target instance.
+func (r *OrganizationReconciler) findOrganizationsForSecret(secret client.Object) []reconcile.Request {
+	ctx := context.Background()
+	key := secret.GetNamespace() + "/" + secret.GetName()
+
+	requests := map[types.NamespacedName]struct{}{}
+
+	var organizations paradoxv1alpha1.OrganizationList
+	if err := r.List(ctx, &organizations, client.MatchingFields{secretField: key}); err != nil {
+		return []reconcile.Request{}
+	}
+
+	for _, organization := range organizations.Items {
+		requests[client.ObjectKeyFromObject(&organization)] = struct{}{}
+	}
+
+	var instances paradoxv1alpha1.InstanceList
+	if err := r.List(ctx, &instances, client.MatchingFields{secretField: key}); err != nil {
+		return []reconcile.Request{}
+	}
+
+	for _, instance := range instances.Items {
+		for _, request := range r.findOrganizationsForInstance(&instance) {
+			requests[request.NamespacedName] = struct{}{}
+		}
+	}
+
+	return toRequests(requests)
+}
+
+// toRequests returns a request for each key.
+func toRequests(keys map[types.NamespacedName]struct{}) []reconcile.Request {
+	requests := make([]reconcile.Request, 0, len(keys))
+	for key := range keys {
+		requests = append(requests, reconcile.Request{NamespacedName: key})
+	}
+
+	return requests
+}
+
+// instanceError is an error which occurred while configuring a particular target instance.
+type instanceError struct {
+	Namespace string
+	Name      string
+	Err       error
+}
+
+func (e *instanceError) Error() string {
+	return fmt.Sprintf("influx instance '%s/%s': %s", e.Namespace, e.Name, e.Err)
+}
+
+func (e *instanceError) Unwrap() error {
+	return e.Err
+}
+
+// forEachInstanceClient calls fn with a client for every instance referenced, or selected, by organization.
+// Instances are visited concurrently, bounded by maxConcurrentInstances. A failure for one
+// instance does not prevent the remaining instances from being visited; all errors are
+// returned together as an aggregate of instanceError.
 func forEachInstanceClient(
 	ctx context.Context,
 	client client.Client,
+	pool *ClientPool,
 	organization *paradoxv1alpha1.Organization,
 	fn func(instance *paradoxv1alpha1.Instance, client influxdb.Client) error,
 ) error {
-	for namespace, namespacedInstances := range organization.Spec.InstanceRefs {
-		for name, auth := range namespacedInstances {
-			var instance paradoxv1alpha1.Instance
-			if err := client.Get(ctx, types.NamespacedName{
-				Namespace: namespace,
-				Name:      name,
-			}, &instance); err != nil {
-				return err
-			}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		sem  = make(chan struct{}, maxConcurrentInstances)
+	)
+
+	targets, err := instanceTargets(ctx, client, organization)
+	if err != nil {
+		return err
+	}
 
-			var token string
-			switch auth.Type {
-			case paradoxv1alpha1.InstanceAuthorizationTypeToken:
-				if auth.Token == nil {
-					return fmt.Errorf("token auth: %w", ErrOrgHasNoAuthorization)
-				}
-				token = *auth.Token
-				break
-
-			case paradoxv1alpha1.InstanceAuthorizationTypeSecret:
-				var secret corev1.Secret
-				if err := client.Get(ctx, types.NamespacedName{
-					Namespace: auth.Secret.Namespace,
-					Name:      auth.Secret.Name,
-				}, &secret); err != nil {
-					return err
-				}
+	for key, target := range targets {
+		key, target := key, target
 
-				tokenBytes, ok := secret.Data[auth.Secret.Key]
-				if !ok {
-					return fmt.Errorf(
-						"secret '%s/%s' key %s auth: %w",
-						auth.Secret.Namespace,
-						auth.Secret.Name,
-						auth.Secret.Key,
-						ErrOrgHasNoAuthorization,
-					)
-				}
+		wg.Add(1)
+		sem <- struct{}{}
 
-				token = string(tokenBytes)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			auth, err := target.authorization(key)
+			if err == nil {
+				err = withInstanceClient(ctx, client, pool, key.Namespace, key.Name, credentialSourceOf(organization, auth), auth, fn)
 			}
 
-			return fn(&instance, influxdb.NewClient(instance.Spec.Address, token))
+			if err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+
+				errs = append(errs, &instanceError{Namespace: key.Namespace, Name: key.Name, Err: err})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// withInstanceClient resolves the named instance and its credentials and calls fn
+// with a client configured for it.
+func withInstanceClient(
+	ctx context.Context,
+	client client.Client,
+	pool *ClientPool,
+	namespace, name, source string,
+	auth paradoxv1alpha1.InstanceAuthorization,
+	fn func(instance *paradoxv1alpha1.Instance, client influxdb.Client) error,
+) error {
+	var instance paradoxv1alpha1.Instance
+	if err := client.Get(ctx, types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}, &instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ErrInstanceNotFound
+		}
+
+		return err
+	}
+
+	if auth.Type == paradoxv1alpha1.InstanceAuthorizationTypeInstance {
+		if instance.Spec.Authorization == nil {
+			return fmt.Errorf("instance auth: %w", ErrInstanceHasNoAuthorization)
+		}
+
+		auth = *instance.Spec.Authorization
+		source = operatorCredential
+	}
+
+	token, err := resolveToken(ctx, client, auth)
+	if err != nil {
+		return err
+	}
+
+	conn, err := resolveInstanceConnection(ctx, client, &instance)
+	if err != nil {
+		return err
+	}
+
+	iclient, release := pool.Get(types.NamespacedName{Namespace: namespace, Name: name}, source, conn, token)
+	defer release()
+
+	return fn(&instance, iclient)
+}
+
+// credentialSourceOf returns the source from which auth, as used by organization,
+// is resolved. Secrets are shared by every organization referencing them.
+func credentialSourceOf(organization *paradoxv1alpha1.Organization, auth paradoxv1alpha1.InstanceAuthorization) string {
+	switch {
+	case auth.Type == paradoxv1alpha1.InstanceAuthorizationTypeInstance:
+		return operatorCredential
+	case auth.Type == paradoxv1alpha1.InstanceAuthorizationTypeSecret && auth.Secret != nil:
+		return fmt.Sprintf("secret:%s/%s/%s", auth.Secret.Namespace, auth.Secret.Name, auth.Secret.Key)
+	default:
+		return organizationCredentialSource(client.ObjectKeyFromObject(organization))
+	}
+}
+
+// organizationCredentialSource returns the source of the tokens declared by the
+// organization identified by organization.
+func organizationCredentialSource(organization types.NamespacedName) string {
+	return "organization:" + organization.String()
+}
+
+// operatorClient returns a client for instance authorized using the operator
+// credential defined on the instance itself, along with a function which must
+// be called once the client is no longer used.
+func operatorClient(ctx context.Context, client client.Client, pool *ClientPool, instance *paradoxv1alpha1.Instance) (influxdb.Client, func(), error) {
+	if instance.Spec.Authorization == nil {
+		return nil, nil, ErrInstanceHasNoAuthorization
+	}
+
+	token, err := resolveToken(ctx, client, *instance.Spec.Authorization)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := resolveInstanceConnection(ctx, client, instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	iclient, release := pool.Get(types.NamespacedName{
+		Namespace: instance.ObjectMeta.Namespace,
+		Name:      instance.ObjectMeta.Name,
+	}, operatorCredential, conn, token)
+
+	return iclient, release, nil
+}
+
+// resolveToken returns the token string identified by auth.
+func resolveToken(ctx context.Context, client client.Client, auth paradoxv1alpha1.InstanceAuthorization) (string, error) {
+	switch auth.Type {
+	case paradoxv1alpha1.InstanceAuthorizationTypeToken:
+		if auth.Token == nil {
+			return "", fmt.Errorf("token auth: %w", ErrOrgHasNoAuthorization)
 		}
+
+		return *auth.Token, nil
+
+	case paradoxv1alpha1.InstanceAuthorizationTypeSecret:
+		if auth.Secret == nil {
+			return "", fmt.Errorf("secret auth: %w", ErrOrgHasNoAuthorization)
+		}
+
+		var secret corev1.Secret
+		if err := client.Get(ctx, types.NamespacedName{
+			Namespace: auth.Secret.Namespace,
+			Name:      auth.Secret.Name,
+		}, &secret); err != nil {
+			return "", err
+		}
+
+		tokenBytes, ok := secret.Data[auth.Secret.Key]
+		if !ok {
+			return "", fmt.Errorf(
+				"secret '%s/%s' key %s auth: %w",
+				auth.Secret.Namespace,
+				auth.Secret.Name,
+				auth.Secret.Key,
+				ErrOrgHasNoAuthorization,
+			)
+		}
+
+		return string(tokenBytes), nil
+	}
+
+	return "", fmt.Errorf("auth type %q: %w", auth.Type, ErrOrgHasNoAuthorization)
+}
+
+// resolveSecretKey returns the value of the Secret key identified by ref.
+func resolveSecretKey(ctx context.Context, client client.Client, ref paradoxv1alpha1.SecretRef) (string, error) {
+	var secret corev1.Secret
+	if err := client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+		return "", err
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("secret '%s/%s' has no key %s", ref.Namespace, ref.Name, ref.Key)
 	}
 
-	return nil
+	return string(value), nil
 }
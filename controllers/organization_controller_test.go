@@ -0,0 +1,227 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// newFakeClient returns a client backed by objs, which understands the
+// paradox types along with the built in Kubernetes types.
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := paradoxv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+// instanceErrors returns the error of each instance within err keyed by the
+// namespaced name of the instance.
+func instanceErrors(t *testing.T, err error) map[string]error {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+
+	var agg utilerrors.Aggregate
+	if !errors.As(err, &agg) {
+		t.Fatalf("error %v is not an aggregate", err)
+	}
+
+	errs := map[string]error{}
+	for _, err := range agg.Errors() {
+		var ierr *instanceError
+		if !errors.As(err, &ierr) {
+			t.Fatalf("error %v is not an instance error", err)
+		}
+
+		errs[ierr.Namespace+"/"+ierr.Name] = ierr.Err
+	}
+
+	return errs
+}
+
+func TestForEachInstanceClient(t *testing.T) {
+	token := "token"
+	errFailed := errors.New("failed")
+
+	instance := func(name string) *paradoxv1alpha1.Instance {
+		return &paradoxv1alpha1.Instance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "influx", Name: name},
+			Spec:       paradoxv1alpha1.InstanceSpec{Address: "http://" + name + ":8086"},
+		}
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "influx", Name: "credentials"},
+		Data:       map[string][]byte{"token": []byte(token)},
+	}
+
+	tokenAuth := paradoxv1alpha1.InstanceAuthorization{Type: paradoxv1alpha1.InstanceAuthorizationTypeToken, Token: &token}
+	secretAuth := func(key string) paradoxv1alpha1.InstanceAuthorization {
+		return paradoxv1alpha1.InstanceAuthorization{
+			Type:   paradoxv1alpha1.InstanceAuthorizationTypeSecret,
+			Secret: &paradoxv1alpha1.SecretRef{Namespace: "influx", Name: "credentials", Key: key},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		refs        map[string]paradoxv1alpha1.InstanceAuthorization
+		failing     string
+		wantVisited []string
+		wantErrs    map[string]func(error) bool
+	}{
+		{
+			name:        "every instance",
+			refs:        map[string]paradoxv1alpha1.InstanceAuthorization{"a": tokenAuth, "b": secretAuth("token"), "c": tokenAuth},
+			wantVisited: []string{"a", "b", "c"},
+		},
+		{
+			name:        "missing instance",
+			refs:        map[string]paradoxv1alpha1.InstanceAuthorization{"a": tokenAuth, "missing": tokenAuth},
+			wantVisited: []string{"a"},
+			wantErrs:    map[string]func(error) bool{"influx/missing": func(err error) bool { return errors.Is(err, ErrInstanceNotFound) }},
+		},
+		{
+			name:        "failing instance",
+			refs:        map[string]paradoxv1alpha1.InstanceAuthorization{"a": tokenAuth, "b": tokenAuth, "c": tokenAuth},
+			failing:     "b",
+			wantVisited: []string{"a", "b", "c"},
+			wantErrs:    map[string]func(error) bool{"influx/b": func(err error) bool { return errors.Is(err, errFailed) }},
+		},
+		{
+			name: "missing credentials",
+			refs: map[string]paradoxv1alpha1.InstanceAuthorization{
+				"a": {Type: paradoxv1alpha1.InstanceAuthorizationTypeToken},
+				"b": secretAuth("missing"),
+				"c": tokenAuth,
+			},
+			wantVisited: []string{"c"},
+			wantErrs: map[string]func(error) bool{
+				"influx/a": func(err error) bool { return errors.Is(err, ErrOrgHasNoAuthorization) },
+				"influx/b": func(err error) bool { return errors.Is(err, ErrOrgHasNoAuthorization) },
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeClient(t, instance("a"), instance("b"), instance("c"), secret)
+
+			organization := &paradoxv1alpha1.Organization{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "org"},
+				Spec: paradoxv1alpha1.OrganizationSpec{
+					Name:         "org",
+					InstanceRefs: map[string]map[string]paradoxv1alpha1.InstanceAuthorization{"influx": tt.refs},
+				},
+			}
+
+			var (
+				mu      sync.Mutex
+				visited []string
+			)
+
+			err := forEachInstanceClient(context.Background(), c, NewClientPool(), organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) error {
+				mu.Lock()
+				visited = append(visited, instance.Name)
+				mu.Unlock()
+
+				if client.ServerURL() != instance.Spec.Address {
+					t.Errorf("client for %s has server URL %q", instance.Name, client.ServerURL())
+				}
+
+				if instance.Name == tt.failing {
+					return errFailed
+				}
+
+				return nil
+			})
+
+			sort.Strings(visited)
+			if !reflect.DeepEqual(visited, tt.wantVisited) {
+				t.Errorf("visited %v, want %v", visited, tt.wantVisited)
+			}
+
+			errs := instanceErrors(t, err)
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("forEachInstanceClient() errors = %v, want errors for %d instances", errs, len(tt.wantErrs))
+			}
+
+			for key, match := range tt.wantErrs {
+				if err, ok := errs[key]; !ok || !match(err) {
+					t.Errorf("error for instance %s = %v", key, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCreatedOrganizations(t *testing.T) {
+	id := func(id paradoxv1alpha1.InfluxID) *paradoxv1alpha1.InfluxID { return &id }
+
+	status := paradoxv1alpha1.OrganizationStatus{
+		Instances: paradoxv1alpha1.Instances{
+			"influx": {
+				"created":  {ID: id("0a0b0c0d0e0f0001")},
+				"adopted":  {ID: id("0a0b0c0d0e0f0002")},
+				"replaced": {ID: id("0a0b0c0d0e0f0003")},
+				"failed":   {LastError: "unreachable"},
+			},
+		},
+		CreatedIDs: paradoxv1alpha1.InstanceIDs{
+			"influx": {
+				"created":  "0a0b0c0d0e0f0001",
+				"replaced": "0a0b0c0d0e0f0004",
+			},
+		},
+	}
+
+	got := createdOrganizations(status)
+
+	want := paradoxv1alpha1.Instances{
+		"influx": {"created": {ID: id("0a0b0c0d0e0f0001")}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("createdOrganizations() = %v, want %v", got, want)
+	}
+}
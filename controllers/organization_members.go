@@ -0,0 +1,234 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// usersField indexes organizations by the name of each User declared as a member or owner.
+const usersField = ".spec.users"
+
+var ErrUserNotCreated = errors.New("user has not been created in the target instance")
+
+// managedUsers are the Users within the namespace of an organization, by name.
+type managedUsers map[string]paradoxv1alpha1.User
+
+// fetchUsers returns every User within namespace.
+func fetchUsers(ctx context.Context, c client.Client, namespace string) (managedUsers, error) {
+	var list paradoxv1alpha1.UserList
+	if err := c.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	users := make(managedUsers, len(list.Items))
+	for _, user := range list.Items {
+		users[user.Name] = user
+	}
+
+	return users, nil
+}
+
+// ids returns the name of each user which has been created within instance, by its identifier.
+func (u managedUsers) ids(instance *paradoxv1alpha1.Instance) map[string]string {
+	ids := map[string]string{}
+	for name, user := range u {
+		if id := user.Status.Instances[instance.ObjectMeta.Namespace][instance.ObjectMeta.Name].ID; id != nil {
+			ids[string(*id)] = name
+		}
+	}
+
+	return ids
+}
+
+// declared returns the name of each of the named users by its identifier within instance.
+func (u managedUsers) declared(instance *paradoxv1alpha1.Instance, names []string) (map[string]string, error) {
+	ids := make(map[string]string, len(names))
+	for _, name := range names {
+		user, ok := u[name]
+		if !ok {
+			return nil, fmt.Errorf("user %q not found", name)
+		}
+
+		id := user.Status.Instances[instance.ObjectMeta.Namespace][instance.ObjectMeta.Name].ID
+		if id == nil {
+			return nil, fmt.Errorf("user %q: %w", name, ErrUserNotCreated)
+		}
+
+		ids[string(*id)] = name
+	}
+
+	return ids, nil
+}
+
+// organizationRole manages the users holding a single role within an organization.
+type organizationRole struct {
+	name   string
+	list   func(ctx context.Context, orgID string) ([]string, error)
+	add    func(ctx context.Context, orgID, userID string) error
+	remove func(ctx context.Context, orgID, userID string) error
+}
+
+// organizationRoles returns the member and owner roles of orgAPI.
+func organizationRoles(orgAPI api.OrganizationsAPI) (member, owner organizationRole) {
+	member = organizationRole{
+		name: "member",
+		list: func(ctx context.Context, orgID string) ([]string, error) {
+			members, err := orgAPI.GetMembersWithID(ctx, orgID)
+			if err != nil {
+				return nil, err
+			}
+
+			return resourceUserIDs(*members, func(m domain.ResourceMember) *string { return m.Id }), nil
+		},
+		add: func(ctx context.Context, orgID, userID string) error {
+			_, err := orgAPI.AddMemberWithID(ctx, orgID, userID)
+			return err
+		},
+		remove: orgAPI.RemoveMemberWithID,
+	}
+
+	owner = organizationRole{
+		name: "owner",
+		list: func(ctx context.Context, orgID string) ([]string, error) {
+			owners, err := orgAPI.GetOwnersWithID(ctx, orgID)
+			if err != nil {
+				return nil, err
+			}
+
+			return resourceUserIDs(*owners, func(o domain.ResourceOwner) *string { return o.Id }), nil
+		},
+		add: func(ctx context.Context, orgID, userID string) error {
+			_, err := orgAPI.AddOwnerWithID(ctx, orgID, userID)
+			return err
+		},
+		remove: orgAPI.RemoveOwnerWithID,
+	}
+
+	return member, owner
+}
+
+func resourceUserIDs[T any](resources []T, id func(T) *string) []string {
+	ids := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		if v := id(resource); v != nil {
+			ids = append(ids, *v)
+		}
+	}
+
+	return ids
+}
+
+// sync adds each of the desired users to the role within the organization identified
+// by orgID, and removes each managed user which is no longer desired. Users which are
+// not managed are left untouched. It returns a description of each change made.
+func (r organizationRole) sync(ctx context.Context, orgID string, desired, managed map[string]string) ([]string, error) {
+	current, err := r.list(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("listing %ss: %w", r.name, err)
+	}
+
+	var changes []string
+
+	existing := make(map[string]struct{}, len(current))
+	for _, id := range current {
+		existing[id] = struct{}{}
+
+		if _, ok := desired[id]; ok {
+			continue
+		}
+
+		name, ok := managed[id]
+		if !ok {
+			continue
+		}
+
+		if err := r.remove(ctx, orgID, id); err != nil && !isInfluxNotFound(err) {
+			return changes, fmt.Errorf("removing %s %q: %w", r.name, name, err)
+		}
+
+		changes = append(changes, fmt.Sprintf("removed %s %q", r.name, name))
+	}
+
+	for id, name := range desired {
+		if _, ok := existing[id]; ok {
+			continue
+		}
+
+		if err := r.add(ctx, orgID, id); err != nil {
+			return changes, fmt.Errorf("adding %s %q: %w", r.name, name, err)
+		}
+
+		changes = append(changes, fmt.Sprintf("added %s %q", r.name, name))
+	}
+
+	sort.Strings(changes)
+
+	return changes, nil
+}
+
+// syncUsers declares the members and owners of the organization identified by orgID
+// within instance, where managed users which are no longer declared are removed.
+// It returns a description of each change made.
+func syncUsers(
+	ctx context.Context,
+	orgAPI api.OrganizationsAPI,
+	instance *paradoxv1alpha1.Instance,
+	orgID string,
+	users managedUsers,
+	spec paradoxv1alpha1.OrganizationSpec,
+) ([]string, error) {
+	managed := users.ids(instance)
+
+	members, err := users.declared(instance, spec.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := users.declared(instance, spec.Owners)
+	if err != nil {
+		return nil, err
+	}
+
+	memberRole, ownerRole := organizationRoles(orgAPI)
+
+	var changes []string
+	for _, role := range []struct {
+		role    organizationRole
+		desired map[string]string
+	}{
+		{memberRole, members},
+		{ownerRole, owners},
+	} {
+		roleChanges, err := role.role.sync(ctx, orgID, role.desired, managed)
+		changes = append(changes, roleChanges...)
+		if err != nil {
+			return changes, err
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// permissionResourceField indexes authorizations by each resource named by their permissions.
+const permissionResourceField = ".spec.permissions.resource"
+
+// permissionResourceKey returns the index key of the resource of resourceType named name.
+func permissionResourceKey(resourceType paradoxv1alpha1.ResourceType, name string) string {
+	return string(resourceType) + "/" + name
+}
+
+// resourceResolver resolves the identifier, within the instance identified by
+// instance, of the resource of a permission named by key.
+type resourceResolver func(ctx context.Context, c client.Client, key, instance types.NamespacedName) (*paradoxv1alpha1.InfluxID, error)
+
+// resourceResolvers maps the permission resource types which can be named
+// onto a resolver for the kind which manages resources of that type.
+// It must agree with ResourceType.Referenceable, which admission relies on.
+var resourceResolvers = map[paradoxv1alpha1.ResourceType]resourceResolver{
+	paradoxv1alpha1.ResourceTypeOrgs: resolveFromStatus("organization", func(o *paradoxv1alpha1.Organization) paradoxv1alpha1.Instances {
+		return o.Status.Instances
+	}),
+	paradoxv1alpha1.ResourceTypeBuckets: resolveFromStatus("bucket", func(b *paradoxv1alpha1.Bucket) paradoxv1alpha1.Instances {
+		return b.Status.Instances
+	}),
+	paradoxv1alpha1.ResourceTypeTasks: resolveFromStatus("task", func(t *paradoxv1alpha1.Task) paradoxv1alpha1.Instances {
+		return t.Status.Instances
+	}),
+	paradoxv1alpha1.ResourceTypeChecks: resolveFromStatus("check", func(c *paradoxv1alpha1.Check) paradoxv1alpha1.Instances {
+		return c.Status.Instances
+	}),
+	paradoxv1alpha1.ResourceTypeNotificationEndpoints: resolveFromStatus("notification endpoint", func(e *paradoxv1alpha1.NotificationEndpoint) paradoxv1alpha1.Instances {
+		return e.Status.Instances
+	}),
+	paradoxv1alpha1.ResourceTypeNotificationRules: resolveFromStatus("notification rule", func(r *paradoxv1alpha1.NotificationRule) paradoxv1alpha1.Instances {
+		return r.Status.Instances
+	}),
+	paradoxv1alpha1.ResourceTypeDashboards: resolveFromStatus("dashboard", func(d *paradoxv1alpha1.Dashboard) paradoxv1alpha1.Instances {
+		return d.Status.Instances
+	}),
+	paradoxv1alpha1.ResourceTypeLabels: resolveFromStatus("label", func(l *paradoxv1alpha1.Label) paradoxv1alpha1.Instances {
+		return l.Status.Instances
+	}),
+	paradoxv1alpha1.ResourceTypeUsers: resolveFromStatus("user", func(u *paradoxv1alpha1.User) paradoxv1alpha1.Instances {
+		return u.Status.Instances
+	}),
+}
+
+// resolveFromStatus returns a resolver which fetches the named object of type T
+// and looks up its identifier within the per-instance status returned by instances.
+func resolveFromStatus[T any, PT interface {
+	*T
+	client.Object
+}](kind string, instances func(PT) paradoxv1alpha1.Instances) resourceResolver {
+	return func(ctx context.Context, c client.Client, key, instance types.NamespacedName) (*paradoxv1alpha1.InfluxID, error) {
+		obj := PT(new(T))
+		if err := c.Get(ctx, key, obj); err != nil {
+			return nil, err
+		}
+
+		id := instances(obj)[instance.Namespace][instance.Name].ID
+		if id == nil {
+			return nil, fmt.Errorf("%s %q does not have an ID", kind, key.Name)
+		}
+
+		return id, nil
+	}
+}
+
+// domainPermission converts permission into its Influx representation for the
+// organization identified by orgID within instance. Named resources are resolved
+// through their corresponding kind within namespace, while unnamed resources
+// grant access to every resource of the type within the organization.
+func domainPermission(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	permission paradoxv1alpha1.Permission,
+	orgID *paradoxv1alpha1.InfluxID,
+	instance types.NamespacedName,
+) (domain.Permission, error) {
+	perm := domain.Permission{
+		Action: domain.PermissionAction(permission.Action),
+		Resource: domain.Resource{
+			Type:  domain.ResourceType(permission.Resource.ResourceType),
+			OrgID: toStringPtr(orgID),
+		},
+	}
+
+	// org resources are identified by their own ID alone, where the
+	// organization itself is the only org resource within the organization
+	if permission.Resource.ResourceType == paradoxv1alpha1.ResourceTypeOrgs {
+		perm.Resource.OrgID = nil
+		perm.Resource.Id = toStringPtr(orgID)
+	}
+
+	if permission.Resource.Name == "" {
+		return perm, nil
+	}
+
+	resolve, ok := resourceResolvers[permission.Resource.ResourceType]
+	if !ok {
+		return perm, fmt.Errorf("resource type %q cannot be named, omit the name to grant access to all %s of the organization",
+			permission.Resource.ResourceType, permission.Resource.ResourceType)
+	}
+
+	id, err := resolve(ctx, c, types.NamespacedName{Namespace: namespace, Name: permission.Resource.Name}, instance)
+	if err != nil {
+		return perm, err
+	}
+
+	perm.Resource.Id = toStringPtr(id)
+
+	return perm, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// DefaultResyncInterval is the interval at which resources are reconciled
+	// against their target instances when no other interval is configured.
+	DefaultResyncInterval = 10 * time.Minute
+
+	// ResyncIntervalAnnotation overrides the resync interval of the annotated resource.
+	// The value is a duration (e.g. 5m), where 0 disables periodic resync.
+	ResyncIntervalAnnotation = "paradox.macro.re/resync-interval"
+)
+
+// resyncResult returns the result which schedules the next resync of obj,
+// using the interval from its annotation when present and valid, or else
+// the supplied default interval.
+func resyncResult(ctx context.Context, obj client.Object, interval time.Duration) ctrl.Result {
+	if value, ok := obj.GetAnnotations()[ResyncIntervalAnnotation]; ok {
+		override, err := time.ParseDuration(value)
+		if err == nil && override >= 0 {
+			interval = override
+		} else {
+			log.FromContext(ctx).Info("ignoring invalid resync interval annotation", "value", value)
+		}
+	}
+
+	if interval <= 0 {
+		return ctrl.Result{}
+	}
+
+	return ctrl.Result{RequeueAfter: interval}
+}
+
+// specOrAnnotationChanged filters out updates which change neither the spec
+// (generation) nor the annotations of an object, such as status updates.
+func specOrAnnotationChanged() predicate.Predicate {
+	return predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+const (
+	// RotateAnnotation requests rotation of the tokens of the annotated authorization
+	// whenever its value changes.
+	RotateAnnotation = "paradox.macro.re/rotate"
+
+	// DefaultTokenGracePeriod is the duration for which rotated tokens remain
+	// valid when an authorization does not declare a grace period.
+	DefaultTokenGracePeriod = time.Hour
+)
+
+// tokenRotation holds the rotation settings of an authorization at the
+// time of a reconcile.
+type tokenRotation struct {
+	now         time.Time
+	rotateAfter time.Duration
+	gracePeriod time.Duration
+	request     string
+}
+
+func newTokenRotation(authorization *paradoxv1alpha1.Authorization) (tokenRotation, error) {
+	rotation := tokenRotation{
+		now:         time.Now(),
+		gracePeriod: DefaultTokenGracePeriod,
+		request:     authorization.GetAnnotations()[RotateAnnotation],
+	}
+
+	if authorization.Spec.RotateAfter != "" {
+		rotateAfter, err := time.ParseDuration(authorization.Spec.RotateAfter)
+		if err != nil {
+			return rotation, fmt.Errorf("parsing rotateAfter: %w", err)
+		}
+
+		rotation.rotateAfter = rotateAfter
+	}
+
+	if authorization.Spec.GracePeriod != "" {
+		gracePeriod, err := time.ParseDuration(authorization.Spec.GracePeriod)
+		if err != nil {
+			return rotation, fmt.Errorf("parsing gracePeriod: %w", err)
+		}
+
+		rotation.gracePeriod = gracePeriod
+	}
+
+	return rotation, nil
+}
+
+// reason describes why token must be rotated, given the hash of the permissions
+// it ought to carry, or returns an empty string when it need not be.
+func (t tokenRotation) reason(token paradoxv1alpha1.TokenInstance, permissionsHash string) string {
+	switch {
+	case token.PermissionsHash != permissionsHash:
+		return "permissions changed"
+	case t.rotateAfter > 0 && token.CreatedTime != nil && !t.now.Before(token.CreatedTime.Add(t.rotateAfter)):
+		return "rotateAfter elapsed"
+	case t.request != "" && t.request != token.RotateRequest:
+		return "rotation requested"
+	}
+
+	return ""
+}
+
+// deadline returns the time at which token next needs to be rotated or have
+// a rotated token deleted, or the zero time when neither is pending.
+func (t tokenRotation) deadline(token paradoxv1alpha1.TokenInstance) (next time.Time) {
+	if t.rotateAfter > 0 && token.CreatedTime != nil {
+		next = token.CreatedTime.Add(t.rotateAfter)
+	}
+
+	for _, retired := range token.Retired {
+		if next.IsZero() || retired.DeleteAfter.Time.Before(next) {
+			next = retired.DeleteAfter.Time
+		}
+	}
+
+	return next
+}
+
+// permissionsHash returns a digest identifying the set of permissions.
+// Only the fields which are supplied when creating an authorization are
+// considered, so that permissions read back from an instance compare equal.
+func permissionsHash(permissions []domain.Permission) string {
+	keys := make([]string, 0, len(permissions))
+	for _, perm := range permissions {
+		keys = append(keys, strings.Join([]string{
+			string(perm.Action),
+			string(perm.Resource.Type),
+			fromPtr(perm.Resource.OrgID),
+			fromPtr(perm.Resource.Id),
+		}, "/"))
+	}
+
+	sort.Strings(keys)
+
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(keys, "\n"))))
+}
+
+// authorizationMarker returns the marker which identifies the authorizations
+// created for authorization within the description of each.
+func authorizationMarker(authorization *paradoxv1alpha1.Authorization) string {
+	return "paradox:" + string(authorization.UID)
+}
+
+// authorizationDescription returns the description of the authorizations created
+// for authorization, which carries its marker so that authorizations which were
+// never recorded in status can be recovered.
+func authorizationDescription(authorization *paradoxv1alpha1.Authorization) string {
+	marker := "[" + authorizationMarker(authorization) + "]"
+	if authorization.Spec.Description == "" {
+		return marker
+	}
+
+	return authorization.Spec.Description + " " + marker
+}
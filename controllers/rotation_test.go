@@ -0,0 +1,281 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+func TestNewTokenRotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        paradoxv1alpha1.AuthorizationSpec
+		annotations map[string]string
+		want        tokenRotation
+		wantErr     bool
+	}{
+		{
+			name: "defaults",
+			want: tokenRotation{gracePeriod: DefaultTokenGracePeriod},
+		},
+		{
+			name:        "declared",
+			spec:        paradoxv1alpha1.AuthorizationSpec{RotateAfter: "720h", GracePeriod: "10m"},
+			annotations: map[string]string{RotateAnnotation: "2022-06-01"},
+			want:        tokenRotation{rotateAfter: 720 * time.Hour, gracePeriod: 10 * time.Minute, request: "2022-06-01"},
+		},
+		{
+			name:    "invalid rotateAfter",
+			spec:    paradoxv1alpha1.AuthorizationSpec{RotateAfter: "monthly"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid gracePeriod",
+			spec:    paradoxv1alpha1.AuthorizationSpec{GracePeriod: "1 hour"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorization := &paradoxv1alpha1.Authorization{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec:       tt.spec,
+			}
+
+			got, err := newTokenRotation(authorization)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newTokenRotation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			got.now = time.Time{}
+			if got != tt.want {
+				t.Errorf("newTokenRotation() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenRotationReason(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	created := func(ago time.Duration) *metav1.Time {
+		created := metav1.NewTime(now.Add(-ago))
+		return &created
+	}
+
+	tests := []struct {
+		name     string
+		rotation tokenRotation
+		token    paradoxv1alpha1.TokenInstance
+		want     string
+	}{
+		{
+			name:     "current",
+			rotation: tokenRotation{now: now, rotateAfter: time.Hour},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: created(time.Minute), PermissionsHash: "a"},
+		},
+		{
+			name:     "permissions changed",
+			rotation: tokenRotation{now: now},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: created(time.Minute), PermissionsHash: "b"},
+			want:     "permissions changed",
+		},
+		{
+			name:     "before rotateAfter",
+			rotation: tokenRotation{now: now, rotateAfter: time.Hour},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: created(time.Hour - time.Nanosecond), PermissionsHash: "a"},
+		},
+		{
+			name:     "rotateAfter elapsed",
+			rotation: tokenRotation{now: now, rotateAfter: time.Hour},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: created(time.Hour), PermissionsHash: "a"},
+			want:     "rotateAfter elapsed",
+		},
+		{
+			name:     "rotateAfter unset",
+			rotation: tokenRotation{now: now},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: created(365 * 24 * time.Hour), PermissionsHash: "a"},
+		},
+		{
+			name:     "creation unknown",
+			rotation: tokenRotation{now: now, rotateAfter: time.Hour},
+			token:    paradoxv1alpha1.TokenInstance{PermissionsHash: "a"},
+		},
+		{
+			name:     "rotation requested",
+			rotation: tokenRotation{now: now, request: "2"},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: created(time.Minute), PermissionsHash: "a", RotateRequest: "1"},
+			want:     "rotation requested",
+		},
+		{
+			name:     "rotation request fulfilled",
+			rotation: tokenRotation{now: now, request: "2"},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: created(time.Minute), PermissionsHash: "a", RotateRequest: "2"},
+		},
+		{
+			name:     "rotation request removed",
+			rotation: tokenRotation{now: now},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: created(time.Minute), PermissionsHash: "a", RotateRequest: "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rotation.reason(tt.token, "a"); got != tt.want {
+				t.Errorf("reason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenRotationDeadline(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	created := metav1.NewTime(now.Add(-time.Minute))
+	retired := func(in time.Duration) paradoxv1alpha1.RetiredToken {
+		return paradoxv1alpha1.RetiredToken{ID: "1", DeleteAfter: metav1.NewTime(now.Add(in))}
+	}
+
+	tests := []struct {
+		name     string
+		rotation tokenRotation
+		token    paradoxv1alpha1.TokenInstance
+		want     time.Time
+	}{
+		{
+			name:     "nothing pending",
+			rotation: tokenRotation{now: now},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: &created},
+		},
+		{
+			name:     "rotateAfter",
+			rotation: tokenRotation{now: now, rotateAfter: time.Hour},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: &created},
+			want:     created.Add(time.Hour),
+		},
+		{
+			name:     "grace period ends first",
+			rotation: tokenRotation{now: now, rotateAfter: time.Hour},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: &created, Retired: []paradoxv1alpha1.RetiredToken{retired(30 * time.Minute), retired(10 * time.Minute)}},
+			want:     now.Add(10 * time.Minute),
+		},
+		{
+			name:     "rotateAfter elapses first",
+			rotation: tokenRotation{now: now, rotateAfter: time.Hour},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: &created, Retired: []paradoxv1alpha1.RetiredToken{retired(2 * time.Hour)}},
+			want:     created.Add(time.Hour),
+		},
+		{
+			name:     "grace period without rotateAfter",
+			rotation: tokenRotation{now: now},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: &created, Retired: []paradoxv1alpha1.RetiredToken{retired(time.Hour)}},
+			want:     now.Add(time.Hour),
+		},
+		{
+			name:     "grace period already ended",
+			rotation: tokenRotation{now: now},
+			token:    paradoxv1alpha1.TokenInstance{CreatedTime: &created, Retired: []paradoxv1alpha1.RetiredToken{retired(-time.Minute)}},
+			want:     now.Add(-time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rotation.deadline(tt.token); !got.Equal(tt.want) {
+				t.Errorf("deadline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionsHash(t *testing.T) {
+	str := func(s string) *string { return &s }
+	permission := func(action domain.PermissionAction, resourceType domain.ResourceType, id string) domain.Permission {
+		return domain.Permission{
+			Action: action,
+			Resource: domain.Resource{
+				Type:  resourceType,
+				OrgID: str("0000000000000001"),
+				Id:    str(id),
+			},
+		}
+	}
+
+	read := permission(domain.PermissionActionRead, domain.ResourceTypeBuckets, "0000000000000002")
+	write := permission(domain.PermissionActionWrite, domain.ResourceTypeBuckets, "0000000000000002")
+
+	readBack := read
+	readBack.Resource.Name = str("telegraf")
+	readBack.Resource.Org = str("example")
+
+	tests := []struct {
+		name  string
+		a, b  []domain.Permission
+		equal bool
+	}{
+		{
+			name:  "order",
+			a:     []domain.Permission{read, write},
+			b:     []domain.Permission{write, read},
+			equal: true,
+		},
+		{
+			name:  "names read back from the instance",
+			a:     []domain.Permission{read},
+			b:     []domain.Permission{readBack},
+			equal: true,
+		},
+		{
+			name: "action",
+			a:    []domain.Permission{read},
+			b:    []domain.Permission{write},
+		},
+		{
+			name: "resource",
+			a:    []domain.Permission{read},
+			b:    []domain.Permission{permission(domain.PermissionActionRead, domain.ResourceTypeBuckets, "0000000000000003")},
+		},
+		{
+			name: "added",
+			a:    []domain.Permission{read},
+			b:    []domain.Permission{read, write},
+		},
+		{
+			name:  "empty",
+			a:     nil,
+			b:     []domain.Permission{},
+			equal: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := permissionsHash(tt.a) == permissionsHash(tt.b); got != tt.equal {
+				t.Errorf("permissionsHash(a) == permissionsHash(b) is %v, want %v", got, tt.equal)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// reconcileInstances calls fn for every target instance of organization and records
+// the resulting resource identifier, or error, against each instance.
+// Entries from previous are carried over so that identifiers and conditions
+// survive failed attempts. When every instance succeeds, entries for instances
+// which are no longer targeted by the organization are dropped.
+func reconcileInstances(
+	ctx context.Context,
+	c client.Client,
+	pool *ClientPool,
+	organization *paradoxv1alpha1.Organization,
+	previous paradoxv1alpha1.Instances,
+	fn func(instance *paradoxv1alpha1.Instance, client influxdb.Client) (*paradoxv1alpha1.InfluxID, error),
+) (paradoxv1alpha1.Instances, error) {
+	instances := previous.DeepCopy()
+	if instances == nil {
+		instances = paradoxv1alpha1.Instances{}
+	}
+
+	var (
+		mu      sync.Mutex
+		visited = map[types.NamespacedName]struct{}{}
+	)
+
+	err := forEachInstanceClient(ctx, c, pool, organization, func(instance *paradoxv1alpha1.Instance, iclient influxdb.Client) error {
+		id, err := fn(instance, iclient)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		visited[client.ObjectKeyFromObject(instance)] = struct{}{}
+
+		instances.AddInstance(instance, id)
+		if err != nil {
+			instances.AddInstanceError(instance, err)
+		}
+
+		return err
+	})
+	if err != nil {
+		// record errors for instances which could not be resolved
+		// and so were never passed to fn
+		var agg utilerrors.Aggregate
+		if errors.As(err, &agg) {
+			for _, err := range agg.Errors() {
+				var ierr *instanceError
+				if !errors.As(err, &ierr) {
+					continue
+				}
+
+				key := types.NamespacedName{Namespace: ierr.Namespace, Name: ierr.Name}
+				if _, ok := visited[key]; ok {
+					continue
+				}
+
+				instance := &paradoxv1alpha1.Instance{}
+				instance.ObjectMeta.Namespace, instance.ObjectMeta.Name = key.Namespace, key.Name
+				instances.AddInstanceError(instance, ierr.Err)
+			}
+		}
+
+		return instances, err
+	}
+
+	for namespace, namespaced := range instances {
+		for name := range namespaced {
+			if _, ok := visited[types.NamespacedName{Namespace: namespace, Name: name}]; !ok {
+				delete(namespaced, name)
+			}
+		}
+
+		if len(namespaced) == 0 {
+			delete(instances, namespace)
+		}
+	}
+
+	return instances, nil
+}
+
+// setConditions updates the Ready, Synced and Degraded conditions based on
+// the outcome of a reconcile, where err is the error encountered (if any)
+// and instances is the resulting per-instance status.
+func setConditions(conditions *[]metav1.Condition, generation int64, instances paradoxv1alpha1.Instances, err error) {
+	var synced int
+	for _, namespaced := range instances {
+		for _, resource := range namespaced {
+			if resource.ID != nil && resource.LastError == "" {
+				synced++
+			}
+		}
+	}
+
+	if err != nil {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               paradoxv1alpha1.ConditionSynced,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             "ReconcileFailed",
+			Message:            err.Error(),
+		})
+
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               paradoxv1alpha1.ConditionReady,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             "ReconcileFailed",
+			Message:            err.Error(),
+		})
+
+		degraded := metav1.Condition{
+			Type:               paradoxv1alpha1.ConditionDegraded,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             "NoInstancesSynced",
+		}
+
+		if synced > 0 {
+			degraded.Status = metav1.ConditionTrue
+			degraded.Reason = "PartiallySynced"
+			degraded.Message = err.Error()
+		}
+
+		meta.SetStatusCondition(conditions, degraded)
+
+		return
+	}
+
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               paradoxv1alpha1.ConditionSynced,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "ReconcileSucceeded",
+	})
+
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               paradoxv1alpha1.ConditionDegraded,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             "ReconcileSucceeded",
+	})
+
+	ready := metav1.Condition{
+		Type:               paradoxv1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "ReconcileSucceeded",
+	}
+
+	if synced == 0 {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "NoInstancesSynced"
+		ready.Message = "resource has not been created in any target instance"
+	}
+
+	meta.SetStatusCondition(conditions, ready)
+}
+
+// setDriftCondition updates the DriftCorrected condition with the drift
+// corrected across every target instance during a reconcile, if any.
+func setDriftCondition(conditions *[]metav1.Condition, generation int64, drift []string) {
+	condition := metav1.Condition{
+		Type:               paradoxv1alpha1.ConditionDriftCorrected,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             "NoDrift",
+	}
+
+	if len(drift) > 0 {
+		sort.Strings(drift)
+
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "DriftCorrected"
+		condition.Message = strings.Join(drift, "; ")
+	}
+
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// setTokenTargetConflictCondition updates the TokenTargetConflict condition with
+// the token targets which could not be written as they are not managed by the
+// authorization, or are shared by instances, if any.
+func setTokenTargetConflictCondition(conditions *[]metav1.Condition, generation int64, conflicts []string) {
+	condition := metav1.Condition{
+		Type:               paradoxv1alpha1.ConditionTokenTargetConflict,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             "NoConflict",
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "TargetConflict"
+		condition.Message = "not managed by the authorization for the instance: " + strings.Join(conflicts, ", ")
+	}
+
+	meta.SetStatusCondition(conditions, condition)
+}
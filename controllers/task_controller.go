@@ -0,0 +1,458 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+// scriptConfigMapField indexes tasks by the ConfigMap holding their script.
+const scriptConfigMapField = ".spec.scriptConfigMapRef"
+
+// TaskReconciler reconciles a Task object
+type TaskReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Clients is the pool of Influx clients shared by every reconciler.
+	Clients *ClientPool
+	// ResyncInterval is the interval at which each task is reconciled
+	// against its target instances, unless overridden by annotation.
+	ResyncInterval time.Duration
+}
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=tasks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=tasks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=tasks/finalizers,verbs=update
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations/status,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=labels,verbs=get;list;watch
+
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// Tasks which already exist are compared against the spec and any drift in
+// their script, schedule, status or description is corrected.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *TaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var task paradoxv1alpha1.Task
+	if err := r.Get(ctx, req.NamespacedName, &task); err != nil {
+		log.Error(err, "unable to fetch task")
+
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	log = log.WithValues("task", task)
+
+	if !task.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := finalize(ctx, r.Client, &task, task.Spec.DeletionPolicy, func() error {
+			return r.deleteInstanceTasks(ctx, &task)
+		}); err != nil {
+			log.Error(err, "failed to finalize task")
+
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if err := syncFinalizer(ctx, r.Client, &task, task.Spec.DeletionPolicy); err != nil {
+		log.Error(err, "failed to update finalizers")
+
+		return ctrl.Result{}, err
+	}
+
+	var organization paradoxv1alpha1.Organization
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: req.NamespacedName.Namespace,
+		Name:      task.Spec.Organization,
+	}, &organization); err != nil {
+		log.Error(err, "unable to fetch organization")
+
+		return ctrl.Result{}, client.IgnoreNotFound(r.updateStatus(ctx, &task, task.Status.Instances, task.Status.Runs, task.Status.LabelMappings, fmt.Errorf("organization %q: %w", task.Spec.Organization, err)))
+	}
+
+	script, err := r.resolveScript(ctx, &task)
+	if err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, &task, task.Status.Instances, task.Status.Runs, task.Status.LabelMappings, err)
+	}
+
+	desired := domainTask(task.Spec, script)
+
+	labels, err := fetchLabels(ctx, r.Client, req.NamespacedName.Namespace, task.Spec.Organization, task.Spec.Labels)
+	if err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, &task, task.Status.Instances, task.Status.Runs, task.Status.LabelMappings, err)
+	}
+
+	var (
+		mu       sync.Mutex
+		drift    []string
+		runs     = task.Status.Runs.DeepCopy()
+		mappings = newLabelMappings(task.Status.LabelMappings)
+	)
+
+	if runs == nil {
+		runs = paradoxv1alpha1.TaskRuns{}
+	}
+
+	instances, err := reconcileInstances(ctx, r.Client, r.Clients, &organization, task.Status.Instances, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) (*paradoxv1alpha1.InfluxID, error) {
+		namespace, name := instance.ObjectMeta.Namespace, instance.ObjectMeta.Name
+
+		orgID, err := organizationID(&organization, instance)
+		if err != nil {
+			return nil, err
+		}
+
+		tasksAPI := client.TasksAPI()
+		existing, err := findTask(ctx, tasksAPI, task.Status.Instances[namespace][name].ID, task.Spec.Name, orgID)
+		if err != nil {
+			return nil, err
+		}
+
+		var changes []string
+
+		if existing == nil {
+			// create task if not exists
+
+			create := *desired
+			create.OrgID = orgID
+
+			existing, err = createTask(ctx, client, &create)
+			if err != nil {
+				return nil, err
+			}
+		} else if changes = taskDrift(existing, desired); len(changes) > 0 {
+			// update task if it exists and differs, where the schedule
+			// is taken from the task option within the script
+
+			existing.Flux = desired.Flux
+			existing.Status = desired.Status
+			existing.Description = desired.Description
+			existing.Every, existing.Cron, existing.Offset = nil, nil, nil
+
+			existing, err = tasksAPI.UpdateTask(ctx, existing)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		labelDrift, err := labels.syncLabelMappings(ctx, client.HTTPService(), instance, "tasks", paradoxv1alpha1.InfluxID(existing.Id), mappings)
+		changes = append(changes, labelDrift...)
+
+		if len(changes) > 0 {
+			message := fmt.Sprintf("corrected drift in instance %s/%s: %s", namespace, name, strings.Join(changes, ", "))
+			r.Recorder.Event(&task, corev1.EventTypeNormal, "DriftCorrected", message)
+
+			mu.Lock()
+			drift = append(drift, message)
+			mu.Unlock()
+		}
+
+		if err != nil {
+			return fromStringPtr[paradoxv1alpha1.InfluxID](&existing.Id), err
+		}
+
+		run := paradoxv1alpha1.TaskRun{
+			LastRunError: fromPtr(existing.LastRunError),
+		}
+
+		if existing.LastRunStatus != nil {
+			run.LastRunStatus = string(*existing.LastRunStatus)
+		}
+
+		if existing.LatestCompleted != nil {
+			completed := metav1.NewTime(*existing.LatestCompleted)
+			run.LatestCompleted = &completed
+		}
+
+		mu.Lock()
+		runs.Set(namespace, name, run)
+		mu.Unlock()
+
+		return fromStringPtr[paradoxv1alpha1.InfluxID](&existing.Id), nil
+	})
+	if err != nil {
+		log.Error(err, "error while configuring instances")
+	}
+
+	setDriftCondition(&task.Status.Conditions, task.Generation, drift)
+
+	return resyncResult(ctx, &task, r.ResyncInterval), r.updateStatus(ctx, &task, instances, runs, mappings.assigned, err)
+}
+
+// updateStatus records instances, runs and label mappings along with the conditions
+// derived from reconcileErr in the status of task. The reconcile error is returned
+// unless the status update itself fails.
+func (r *TaskReconciler) updateStatus(ctx context.Context, task *paradoxv1alpha1.Task, instances paradoxv1alpha1.Instances, runs paradoxv1alpha1.TaskRuns, mappings paradoxv1alpha1.InstanceLabels, reconcileErr error) error {
+	if instances == nil {
+		instances = paradoxv1alpha1.Instances{}
+	}
+
+	// runs are only kept for instances which are still recorded
+	runs.Retain(instances)
+
+	// label mappings are only kept for instances which are still recorded
+	mappings.Retain(instances)
+
+	task.Status.ObservedGeneration = task.Generation
+	task.Status.Instances = instances
+	task.Status.Runs = runs
+	task.Status.LabelMappings = mappings
+	setConditions(&task.Status.Conditions, task.Generation, instances, reconcileErr)
+
+	if err := r.Status().Update(ctx, task); err != nil {
+		log.FromContext(ctx).Error(err, "failed to update status")
+
+		return err
+	}
+
+	return reconcileErr
+}
+
+// resolveScript returns the Flux script of task, either declared inline
+// or read from the referenced ConfigMap.
+func (r *TaskReconciler) resolveScript(ctx context.Context, task *paradoxv1alpha1.Task) (string, error) {
+	ref := task.Spec.ScriptConfigMapRef
+	if ref == nil {
+		return task.Spec.Script, nil
+	}
+
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &configMap); err != nil {
+		return "", fmt.Errorf("script config map: %w", err)
+	}
+
+	script, ok := configMap.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("script config map '%s/%s' has no key %s", ref.Namespace, ref.Name, ref.Key)
+	}
+
+	// inline scripts are checked on admission, whereas the content of a
+	// config map is only known once it is resolved
+	if paradoxv1alpha1.DeclaresTaskOption(script) {
+		return "", fmt.Errorf("script config map '%s/%s' key %s must not declare the task option, which is generated from the spec", ref.Namespace, ref.Name, ref.Key)
+	}
+
+	return script, nil
+}
+
+// deleteInstanceTasks removes the task from every target instance in which
+// it has previously been recorded.
+func (r *TaskReconciler) deleteInstanceTasks(ctx context.Context, task *paradoxv1alpha1.Task) error {
+	deletion := instanceDeletion{
+		Client:    r.Client,
+		Clients:   r.Clients,
+		Recorder:  r.Recorder,
+		Object:    task,
+		Noun:      "task",
+		Instances: task.Status.Instances,
+	}
+
+	return deletion.deleteWithin(ctx, task.Spec.Organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client, id paradoxv1alpha1.InfluxID) error {
+		if err := client.TasksAPI().DeleteTaskWithID(ctx, string(id)); err != nil && !isInfluxNotFound(err) {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// findTask returns the task previously recorded as id, or else the task named
+// name within the organization identified by orgID, or nil when neither exists.
+func findTask(ctx context.Context, tasksAPI api.TasksAPI, id *paradoxv1alpha1.InfluxID, name, orgID string) (*domain.Task, error) {
+	if id != nil {
+		task, err := tasksAPI.GetTaskByID(ctx, string(*id))
+		if err == nil {
+			return task, nil
+		}
+
+		if !isInfluxNotFound(err) {
+			return nil, err
+		}
+	}
+
+	tasks, err := tasksAPI.FindTasks(ctx, &api.TaskFilter{Name: name, OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		if task.Name == name {
+			return &task, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// createTask creates task with its Flux script as given. The Influx client only
+// creates tasks by prepending a task option of its own, which cannot declare an
+// offset, and so the task is posted directly through the generated API client.
+func createTask(ctx context.Context, client influxdb.Client, task *domain.Task) (*domain.Task, error) {
+	response, err := domain.NewClientWithResponses(client.HTTPService()).PostTasksWithResponse(ctx, &domain.PostTasksParams{},
+		domain.PostTasksJSONRequestBody{
+			Flux:        task.Flux,
+			OrgID:       &task.OrgID,
+			Status:      task.Status,
+			Description: task.Description,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	if response.JSONDefault != nil {
+		return nil, domain.ErrorToHTTPError(response.JSONDefault, response.StatusCode())
+	}
+
+	if response.JSON201 == nil {
+		return nil, fmt.Errorf("%w: task creation returned %s", ErrInfluxUnexpectedResponse, response.Status())
+	}
+
+	return response.JSON201, nil
+}
+
+// domainTask returns the Influx representation of the task defined by spec,
+// running script under a task option generated from its name and schedule.
+func domainTask(spec paradoxv1alpha1.TaskSpec, script string) *domain.Task {
+	options := []string{"name: " + strconv.Quote(spec.Name)}
+	if spec.Every != "" {
+		options = append(options, "every: "+spec.Every)
+	}
+
+	if spec.Cron != "" {
+		options = append(options, "cron: "+strconv.Quote(spec.Cron))
+	}
+
+	if spec.Offset != "" {
+		options = append(options, "offset: "+spec.Offset)
+	}
+
+	status := domain.TaskStatusTypeActive
+	if spec.Status == paradoxv1alpha1.ActivityStatusInactive {
+		status = domain.TaskStatusTypeInactive
+	}
+
+	description := spec.Description
+
+	return &domain.Task{
+		Name:        spec.Name,
+		Flux:        fmt.Sprintf("option task = {%s}\n\n%s\n", strings.Join(options, ", "), strings.TrimSpace(script)),
+		Status:      &status,
+		Description: &description,
+	}
+}
+
+// taskDrift describes each difference between the existing task and the desired task.
+// The schedule of a task is declared by the task option of its script, and so
+// any change to it is reported as drift in the script.
+func taskDrift(existing, desired *domain.Task) (changes []string) {
+	if strings.TrimSpace(existing.Flux) != strings.TrimSpace(desired.Flux) {
+		changes = append(changes, "script")
+	}
+
+	existingStatus := domain.TaskStatusTypeActive
+	if existing.Status != nil {
+		existingStatus = *existing.Status
+	}
+
+	if existingStatus != *desired.Status {
+		changes = append(changes, fmt.Sprintf("status %s -> %s", existingStatus, *desired.Status))
+	}
+
+	if fromPtr(existing.Description) != fromPtr(desired.Description) {
+		changes = append(changes, fmt.Sprintf("description %q -> %q", fromPtr(existing.Description), fromPtr(desired.Description)))
+	}
+
+	return changes
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TaskReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.Task{}, orgField, func(rawObj client.Object) []string {
+		task := rawObj.(*paradoxv1alpha1.Task)
+		if task.Spec.Organization == "" {
+			return nil
+		}
+
+		return []string{task.Spec.Organization}
+	}); err != nil {
+		return err
+	}
+
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.Task{}, scriptConfigMapField, func(rawObj client.Object) []string {
+		task := rawObj.(*paradoxv1alpha1.Task)
+		if ref := task.Spec.ScriptConfigMapRef; ref != nil {
+			return []string{types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}.String()}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := indexLabels(mgr, &paradoxv1alpha1.Task{}, func(rawObj client.Object) []string {
+		return rawObj.(*paradoxv1alpha1.Task).Spec.Labels
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&paradoxv1alpha1.Task{}, builder.WithPredicates(specOrAnnotationChanged())).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Organization{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.TaskList{}, orgField)),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(findForReference(r.Client, &paradoxv1alpha1.TaskList{}, scriptConfigMapField)),
+		).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Label{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.TaskList{}, labelsField)),
+		).
+		Complete(r)
+}
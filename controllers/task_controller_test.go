@@ -0,0 +1,101 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+func TestCreateTask(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		wantID      string
+		wantErr     error
+		wantAnyErr  bool
+	}{
+		{
+			name:        "created",
+			status:      nethttp.StatusCreated,
+			contentType: "application/json",
+			body:        `{"id": "0a0b0c0d0e0f0001", "orgID": "0a0b0c0d0e0f0002", "name": "downsample", "flux": "from(bucket: \"metrics\")"}`,
+			wantID:      "0a0b0c0d0e0f0001",
+		},
+		{
+			name:        "influx error",
+			status:      nethttp.StatusBadRequest,
+			contentType: "application/json",
+			body:        `{"code": "invalid", "message": "failed to decode request body"}`,
+			wantAnyErr:  true,
+		},
+		{
+			name:        "proxy login page",
+			status:      nethttp.StatusOK,
+			contentType: "text/html",
+			body:        "<html><body>Sign in to continue</body></html>",
+			wantErr:     ErrInfluxUnexpectedResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			iclient := influxdb.NewClient(server.URL, "secret")
+			defer iclient.Close()
+
+			task := domainTask(paradoxv1alpha1.TaskSpec{Name: "downsample", Every: "1h"}, `from(bucket: "metrics")`)
+			task.OrgID = "0a0b0c0d0e0f0002"
+
+			got, err := createTask(context.Background(), iclient, task)
+			if tt.wantErr != nil || tt.wantAnyErr {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("createTask() error = %v, want %v", err, tt.wantErr)
+				}
+
+				if got != nil {
+					t.Errorf("createTask() = %+v alongside error", got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("createTask() error = %v", err)
+			}
+
+			if got == nil || got.Id != tt.wantID {
+				t.Errorf("createTask() = %+v, want task %s", got, tt.wantID)
+			}
+		})
+	}
+}
@@ -0,0 +1,601 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+const (
+	// labels tying each token Secret and ConfigMap back to the Authorization and Instance it was issued for
+	authorizationNameLabel      = "paradox.macro.re/authorization"
+	authorizationNamespaceLabel = "paradox.macro.re/authorization-namespace"
+	instanceNameLabel           = "paradox.macro.re/instance"
+	instanceNamespaceLabel      = "paradox.macro.re/instance-namespace"
+
+	// authorizationIDAnnotation records the identifier of the authorization whose
+	// token a target was last written with.
+	authorizationIDAnnotation = "paradox.macro.re/authorization-id"
+)
+
+// tokenTargetConflictError is returned for a token target which already exists
+// and is not managed by the authorization publishing the token, or which is also
+// a target of the token of the authorization for another instance.
+type tokenTargetConflictError struct {
+	kind string
+	key  types.NamespacedName
+	// instance identifies the other instance, when the target names of both collide.
+	instance types.NamespacedName
+}
+
+func (e *tokenTargetConflictError) Error() string {
+	if e.instance.Name != "" {
+		return fmt.Sprintf("also a target of the token for instance %s", e.instance)
+	}
+
+	return "already exists and is not managed by the authorization"
+}
+
+// tokenTargetConflicts returns the kind and name of each conflicting token target within err.
+func tokenTargetConflicts(err error) (conflicts []string) {
+	var agg utilerrors.Aggregate
+	if errors.As(err, &agg) {
+		for _, err := range agg.Errors() {
+			conflicts = append(conflicts, tokenTargetConflicts(err)...)
+		}
+
+		return conflicts
+	}
+
+	var conflict *tokenTargetConflictError
+	if errors.As(err, &conflict) {
+		if conflict.instance.Name != "" {
+			return []string{fmt.Sprintf("%s %s (instance %s)", strings.ToLower(conflict.kind), conflict.key, conflict.instance)}
+		}
+
+		return []string{fmt.Sprintf("%s %s", strings.ToLower(conflict.kind), conflict.key)}
+	}
+
+	return nil
+}
+
+// tokenTemplateData is supplied to the templates of the targets in which the
+// token of an authorization is published for a single instance.
+type tokenTemplateData struct {
+	Instance struct {
+		Namespace string
+		Name      string
+		Address   string
+	}
+	Organization resourceTemplateData
+	// Bucket is the first of the buckets named by the permissions of the authorization.
+	Bucket resourceTemplateData
+	// Buckets are the buckets named by the permissions of the authorization.
+	Buckets       []resourceTemplateData
+	Authorization struct {
+		Namespace string
+		Name      string
+		ID        string
+	}
+	Token string
+}
+
+// resourceTemplateData identifies an Influx resource within templates.
+type resourceTemplateData struct {
+	Name string
+	ID   string
+}
+
+// tokenTemplateData returns the template data describing the token of authorization
+// within instance, where organization has the identifier orgID. The authorization ID
+// and token are left to be filled in once known.
+func (r *AuthorizationReconciler) tokenTemplateData(
+	ctx context.Context,
+	authorization *paradoxv1alpha1.Authorization,
+	organization *paradoxv1alpha1.Organization,
+	instance *paradoxv1alpha1.Instance,
+	orgID *paradoxv1alpha1.InfluxID,
+) (tokenTemplateData, error) {
+	var data tokenTemplateData
+	data.Instance.Namespace = instance.Namespace
+	data.Instance.Name = instance.Name
+	data.Instance.Address = instance.Spec.Address
+	data.Organization = resourceTemplateData{Name: organization.Spec.Name, ID: string(fromPtr(orgID))}
+	data.Authorization.Namespace = authorization.Namespace
+	data.Authorization.Name = authorization.Name
+
+	seen := map[string]struct{}{}
+	for _, permission := range authorization.Spec.Permissions {
+		if permission.Resource.ResourceType != paradoxv1alpha1.ResourceTypeBuckets || permission.Resource.Name == "" {
+			continue
+		}
+
+		if _, ok := seen[permission.Resource.Name]; ok {
+			continue
+		}
+
+		seen[permission.Resource.Name] = struct{}{}
+
+		var bucket paradoxv1alpha1.Bucket
+		if err := r.Get(ctx, types.NamespacedName{
+			Namespace: authorization.Namespace,
+			Name:      permission.Resource.Name,
+		}, &bucket); err != nil {
+			return data, err
+		}
+
+		data.Buckets = append(data.Buckets, resourceTemplateData{
+			Name: bucket.Spec.Name,
+			ID:   string(fromPtr(bucket.Status.Instances[instance.Namespace][instance.Name].ID)),
+		})
+	}
+
+	if len(data.Buckets) > 0 {
+		data.Bucket = data.Buckets[0]
+	}
+
+	return data, nil
+}
+
+// renderTemplate executes the text template text with data.
+func renderTemplate(text string, data tokenTemplateData) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// renderTemplates executes each of the text templates in texts with data.
+func renderTemplates(texts map[string]string, data tokenTemplateData) (map[string]string, error) {
+	rendered := make(map[string]string, len(texts))
+	for key, text := range texts {
+		value, err := renderTemplate(text, data)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", key, err)
+		}
+
+		rendered[key] = value
+	}
+
+	return rendered, nil
+}
+
+// tokenTarget is a single Secret or ConfigMap in which details of a token are published.
+type tokenTarget struct {
+	kind string
+	key  types.NamespacedName
+	spec paradoxv1alpha1.TargetSpec
+	// tokenKey is the data key under which a Secret target stores the token.
+	tokenKey string
+}
+
+const (
+	tokenTargetSecret    = "Secret"
+	tokenTargetConfigMap = "ConfigMap"
+)
+
+// tokenDelivery describes the token of an authorization within a single instance
+// along with every target in which the token is to be published.
+type tokenDelivery struct {
+	data    tokenTemplateData
+	targets []tokenTarget
+	// targeted is every instance targeted by the organization of the authorization,
+	// whose targets are not taken over by the targets of another instance.
+	targeted map[types.NamespacedName]instanceTarget
+}
+
+// claimTokenTargets records each target of delivery in claims as a target of the
+// token for the instance of delivery, unless one is already claimed for another
+// instance, in which case nothing is claimed and the conflicts are returned.
+func claimTokenTargets(claims map[tokenTargetKey]types.NamespacedName, delivery tokenDelivery) error {
+	instance := types.NamespacedName{Namespace: delivery.data.Instance.Namespace, Name: delivery.data.Instance.Name}
+
+	var errs []error
+	for _, target := range delivery.targets {
+		if other, ok := claims[tokenTargetKey{target.kind, target.key}]; ok && other != instance {
+			errs = append(errs, fmt.Errorf("%s %s: %w", strings.ToLower(target.kind), target.key,
+				&tokenTargetConflictError{kind: target.kind, key: target.key, instance: other}))
+		}
+	}
+
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	for _, target := range delivery.targets {
+		claims[tokenTargetKey{target.kind, target.key}] = instance
+	}
+
+	return nil
+}
+
+// resolveTokenDelivery resolves every target declared by the token spec of authorization
+// for the instance described by data. The target names cannot refer to the
+// authorization ID or token, as both change whenever the token is rotated.
+func (r *AuthorizationReconciler) resolveTokenDelivery(ctx context.Context, authorization *paradoxv1alpha1.Authorization, data tokenTemplateData) (tokenDelivery, error) {
+	delivery := tokenDelivery{data: data}
+
+	nameData := data
+	nameData.Authorization.ID, nameData.Token = "", ""
+
+	add := func(kind string, spec paradoxv1alpha1.TargetSpec, tokenKey string) error {
+		name, err := renderTemplate(spec.NameTemplate, nameData)
+		if err != nil {
+			return fmt.Errorf("rendering %s name: %w", strings.ToLower(kind), err)
+		}
+
+		namespaces, err := r.targetNamespaces(ctx, spec)
+		if err != nil {
+			return err
+		}
+
+		for _, namespace := range namespaces {
+			delivery.targets = append(delivery.targets, tokenTarget{
+				kind:     kind,
+				key:      types.NamespacedName{Namespace: namespace, Name: name},
+				spec:     spec,
+				tokenKey: tokenKey,
+			})
+		}
+
+		return nil
+	}
+
+	token := authorization.Spec.Token
+
+	secrets := token.Secrets
+	if token.SecretSpec != nil {
+		secrets = append([]paradoxv1alpha1.SecretSpec{*token.SecretSpec}, secrets...)
+	}
+
+	for _, spec := range secrets {
+		if err := add(tokenTargetSecret, spec.TargetSpec, spec.Key); err != nil {
+			return delivery, err
+		}
+	}
+
+	for _, spec := range token.ConfigMaps {
+		if err := add(tokenTargetConfigMap, spec.TargetSpec, ""); err != nil {
+			return delivery, err
+		}
+	}
+
+	return delivery, nil
+}
+
+// targetNamespaces returns the namespace of spec along with every namespace
+// matching its namespace selector.
+func (r *AuthorizationReconciler) targetNamespaces(ctx context.Context, spec paradoxv1alpha1.TargetSpec) ([]string, error) {
+	namespaces := map[string]struct{}{}
+	if spec.Namespace != "" {
+		namespaces[spec.Namespace] = struct{}{}
+	}
+
+	if spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("parsing namespace selector: %w", err)
+		}
+
+		var list corev1.NamespaceList
+		if err := r.List(ctx, &list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+
+		for _, namespace := range list.Items {
+			if namespace.DeletionTimestamp.IsZero() {
+				namespaces[namespace.Name] = struct{}{}
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(namespaces))
+	for namespace := range namespaces {
+		sorted = append(sorted, namespace)
+	}
+
+	sort.Strings(sorted)
+
+	return sorted, nil
+}
+
+// tokenTargetLabels returns the labels identifying the token targets of authorization
+// for the instance identified by namespace and name.
+func tokenTargetLabels(authorization *paradoxv1alpha1.Authorization, namespace, name string) map[string]string {
+	return map[string]string{
+		authorizationNameLabel:      authorization.Name,
+		authorizationNamespaceLabel: authorization.Namespace,
+		instanceNameLabel:           name,
+		instanceNamespaceLabel:      namespace,
+	}
+}
+
+// writeTokenTargets creates or updates every target of delivery to publish the
+// token of the authorization identified by id. Secrets hold the token along with
+// their templated data, whereas ConfigMaps only hold their templated data, which
+// cannot refer to the token. Targets within the namespace of the authorization
+// are owned by it, so that they are garbage collected along with it. Existing
+// objects which were not created for the authorization are left untouched.
+// The number of targets written is returned along with any error.
+func (r *AuthorizationReconciler) writeTokenTargets(ctx context.Context, authorization *paradoxv1alpha1.Authorization, delivery tokenDelivery, id paradoxv1alpha1.InfluxID, token string) (written int, err error) {
+	var errs []error
+	for _, target := range delivery.targets {
+		data := delivery.data
+		data.Authorization.ID, data.Token = string(id), token
+
+		if target.kind == tokenTargetConfigMap {
+			data.Token = ""
+		}
+
+		if err := r.writeTokenTarget(ctx, authorization, target, data, delivery.targeted); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", strings.ToLower(target.kind), target.key, err))
+			continue
+		}
+
+		written++
+	}
+
+	return written, utilerrors.NewAggregate(errs)
+}
+
+func (r *AuthorizationReconciler) writeTokenTarget(
+	ctx context.Context,
+	authorization *paradoxv1alpha1.Authorization,
+	target tokenTarget,
+	data tokenTemplateData,
+	targeted map[types.NamespacedName]instanceTarget,
+) error {
+	labels, err := renderTemplates(target.spec.Labels, data)
+	if err != nil {
+		return fmt.Errorf("rendering labels: %w", err)
+	}
+
+	annotations, err := renderTemplates(target.spec.Annotations, data)
+	if err != nil {
+		return fmt.Errorf("rendering annotations: %w", err)
+	}
+
+	values, err := renderTemplates(target.spec.Data, data)
+	if err != nil {
+		return fmt.Errorf("rendering data: %w", err)
+	}
+
+	var obj client.Object
+	switch target.kind {
+	case tokenTargetSecret:
+		obj = &corev1.Secret{}
+	default:
+		obj = &corev1.ConfigMap{}
+	}
+
+	obj.SetNamespace(target.key.Namespace)
+	obj.SetName(target.key.Name)
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		objLabels := obj.GetLabels()
+
+		// existing objects are only written once they are known to have been
+		// created for the authorization, so that neither objects of applications
+		// nor the targets of other authorizations are overwritten
+		if obj.GetResourceVersion() != "" {
+			if objLabels[authorizationNameLabel] != authorization.Name || objLabels[authorizationNamespaceLabel] != authorization.Namespace {
+				return &tokenTargetConflictError{kind: target.kind, key: target.key}
+			}
+
+			// nor are the targets of instances which are still targeted, as when a
+			// name template does not tell instances apart, whereas those of instances
+			// which are no longer targeted are taken over
+			owner := types.NamespacedName{Namespace: objLabels[instanceNamespaceLabel], Name: objLabels[instanceNameLabel]}
+			if _, ok := targeted[owner]; ok && (owner.Namespace != data.Instance.Namespace || owner.Name != data.Instance.Name) {
+				return &tokenTargetConflictError{kind: target.kind, key: target.key, instance: owner}
+			}
+		}
+
+		if objLabels == nil {
+			objLabels = map[string]string{}
+		}
+
+		for k, v := range labels {
+			objLabels[k] = v
+		}
+
+		for k, v := range tokenTargetLabels(authorization, data.Instance.Namespace, data.Instance.Name) {
+			objLabels[k] = v
+		}
+
+		obj.SetLabels(objLabels)
+
+		objAnnotations := obj.GetAnnotations()
+		if objAnnotations == nil {
+			objAnnotations = map[string]string{}
+		}
+
+		for k, v := range annotations {
+			objAnnotations[k] = v
+		}
+
+		objAnnotations[authorizationIDAnnotation] = data.Authorization.ID
+		obj.SetAnnotations(objAnnotations)
+
+		// the data of each target is owned entirely by the authorization
+		switch obj := obj.(type) {
+		case *corev1.Secret:
+			obj.Data = map[string][]byte{}
+			for k, v := range values {
+				obj.Data[k] = []byte(v)
+			}
+
+			obj.Data[target.tokenKey] = []byte(data.Token)
+		case *corev1.ConfigMap:
+			obj.Data = values
+		}
+
+		// owner references cannot cross namespaces
+		if obj.GetNamespace() == authorization.Namespace {
+			return controllerutil.SetControllerReference(authorization, obj, r.Scheme)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// readToken returns the token of the authorization identified by id held by the
+// Secret targets of delivery, and whether one of them holds it. Secrets last written
+// with the token of another authorization, such as when publishing a rotated token
+// failed part way, are skipped. It reports true when there are no Secret targets,
+// as the token then need not be retained.
+func (r *AuthorizationReconciler) readToken(ctx context.Context, delivery tokenDelivery, id paradoxv1alpha1.InfluxID) (string, bool, error) {
+	var secrets int
+	for _, target := range delivery.targets {
+		if target.kind != tokenTargetSecret {
+			continue
+		}
+
+		secrets++
+
+		var secret corev1.Secret
+		if err := r.Get(ctx, target.key, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return "", false, err
+		}
+
+		// secrets written before their authorization was recorded are trusted
+		if written, ok := secret.Annotations[authorizationIDAnnotation]; ok && written != string(id) {
+			continue
+		}
+
+		if token := secret.Data[target.tokenKey]; len(token) > 0 {
+			return string(token), true, nil
+		}
+	}
+
+	return "", secrets == 0, nil
+}
+
+// deleteTokenTargets deletes every Secret and ConfigMap labelled as publishing a token
+// of authorization, across all namespaces, other than the targets in keep.
+func (r *AuthorizationReconciler) deleteTokenTargets(ctx context.Context, authorization *paradoxv1alpha1.Authorization, keep map[tokenTargetKey]struct{}) error {
+	selector := client.MatchingLabels{
+		authorizationNameLabel:      authorization.Name,
+		authorizationNamespaceLabel: authorization.Namespace,
+	}
+
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, selector); err != nil {
+		return err
+	}
+
+	var configMaps corev1.ConfigMapList
+	if err := r.List(ctx, &configMaps, selector); err != nil {
+		return err
+	}
+
+	objs := make([]client.Object, 0, len(secrets.Items)+len(configMaps.Items))
+	for i := range secrets.Items {
+		objs = append(objs, &secrets.Items[i])
+	}
+
+	for i := range configMaps.Items {
+		objs = append(objs, &configMaps.Items[i])
+	}
+
+	var errs []error
+	for _, obj := range objs {
+		kind := tokenTargetSecret
+		if _, ok := obj.(*corev1.ConfigMap); ok {
+			kind = tokenTargetConfigMap
+		}
+
+		if _, ok := keep[tokenTargetKey{kind, client.ObjectKeyFromObject(obj)}]; ok {
+			continue
+		}
+
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// tokenTargetKey identifies a single token target.
+type tokenTargetKey struct {
+	kind string
+	types.NamespacedName
+}
+
+// findAuthorizationForTarget maps a token Secret or ConfigMap onto the Authorization
+// it was published for, as identified by its labels.
+func (r *AuthorizationReconciler) findAuthorizationForTarget(obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+
+	name, namespace := labels[authorizationNameLabel], labels[authorizationNamespaceLabel]
+	if name == "" || namespace == "" {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+}
+
+// findAuthorizationsForNamespace maps a namespace onto every Authorization which
+// selects its token target namespaces by label.
+func (r *AuthorizationReconciler) findAuthorizationsForNamespace(namespace client.Object) []reconcile.Request {
+	var authorizations paradoxv1alpha1.AuthorizationList
+	if err := r.List(context.TODO(), &authorizations); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, authorization := range authorizations.Items {
+		if !authorization.Spec.Token.SelectsNamespaces() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&authorization)})
+	}
+
+	return requests
+}
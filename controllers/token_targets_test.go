@@ -0,0 +1,636 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+func tokenSecretAuthorization(namespace, nameTemplate string) *paradoxv1alpha1.Authorization {
+	return &paradoxv1alpha1.Authorization{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "telegraf", UID: "3c1b8b7e-5d0a-4a43-9d3c-0e2f5b0c9a11"},
+		Spec: paradoxv1alpha1.AuthorizationSpec{
+			Token: paradoxv1alpha1.Token{
+				SecretSpec: &paradoxv1alpha1.SecretSpec{
+					TargetSpec: paradoxv1alpha1.TargetSpec{Namespace: namespace, NameTemplate: nameTemplate},
+					Key:        "token",
+				},
+			},
+		},
+	}
+}
+
+func tokenSecretTemplateData() tokenTemplateData {
+	var data tokenTemplateData
+	data.Instance.Namespace = "influx"
+	data.Instance.Name = "primary"
+	data.Instance.Address = "http://influx:8086"
+	data.Organization = resourceTemplateData{Name: "macro", ID: "0a0b0c0d0e0f0001"}
+	data.Authorization.Namespace = "default"
+	data.Authorization.Name = "telegraf"
+
+	return data
+}
+
+func TestResolveTokenDelivery(t *testing.T) {
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	secret := func(kind, namespace, name string) tokenTargetKey {
+		return tokenTargetKey{kind, types.NamespacedName{Namespace: namespace, Name: name}}
+	}
+
+	tests := []struct {
+		name    string
+		token   paradoxv1alpha1.Token
+		want    []tokenTargetKey
+		wantErr bool
+	}{
+		{
+			name: "no targets",
+		},
+		{
+			name:  "instance name",
+			token: tokenSecretAuthorization("default", "telegraf-{{ .Instance.Name }}").Spec.Token,
+			want:  []tokenTargetKey{secret(tokenTargetSecret, "default", "telegraf-primary")},
+		},
+		{
+			name:  "organization name and instance namespace",
+			token: tokenSecretAuthorization("apps", "{{ .Organization.Name }}-{{ .Instance.Namespace }}").Spec.Token,
+			want:  []tokenTargetKey{secret(tokenTargetSecret, "apps", "macro-influx")},
+		},
+		{
+			name:  "token is not available",
+			token: tokenSecretAuthorization("default", "telegraf-{{ .Token }}{{ .Authorization.ID }}").Spec.Token,
+			want:  []tokenTargetKey{secret(tokenTargetSecret, "default", "telegraf-")},
+		},
+		{
+			name: "secrets and config maps across selected namespaces",
+			token: paradoxv1alpha1.Token{
+				SecretSpec: tokenSecretAuthorization("default", "telegraf").Spec.Token.SecretSpec,
+				Secrets: []paradoxv1alpha1.SecretSpec{{
+					TargetSpec: paradoxv1alpha1.TargetSpec{
+						NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"metrics": "true"}},
+						NameTemplate:      "influx-token",
+					},
+					Key: "INFLUX_TOKEN",
+				}},
+				ConfigMaps: []paradoxv1alpha1.ConfigMapSpec{{
+					TargetSpec: paradoxv1alpha1.TargetSpec{Namespace: "default", NameTemplate: "influx-{{ .Instance.Name }}"},
+				}},
+			},
+			want: []tokenTargetKey{
+				secret(tokenTargetSecret, "default", "telegraf"),
+				secret(tokenTargetSecret, "apps", "influx-token"),
+				secret(tokenTargetSecret, "monitoring", "influx-token"),
+				secret(tokenTargetConfigMap, "default", "influx-primary"),
+			},
+		},
+		{
+			name:    "invalid template",
+			token:   tokenSecretAuthorization("default", "telegraf-{{ .Instance.Name").Spec.Token,
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			token:   tokenSecretAuthorization("default", "telegraf-{{ .Cluster }}").Spec.Token,
+			wantErr: true,
+		},
+	}
+
+	c := newFakeClient(t,
+		namespace("apps", map[string]string{"metrics": "true"}),
+		namespace("monitoring", map[string]string{"metrics": "true"}),
+		namespace("default", nil),
+	)
+	r := &AuthorizationReconciler{Client: c, Scheme: c.Scheme()}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorization := tokenSecretAuthorization("default", "")
+			authorization.Spec.Token = tt.token
+
+			data := tokenSecretTemplateData()
+			data.Authorization.ID, data.Token = "0a0b0c0d0e0f0002", "secret"
+
+			delivery, err := r.resolveTokenDelivery(context.Background(), authorization, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveTokenDelivery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var got []tokenTargetKey
+			for _, target := range delivery.targets {
+				got = append(got, tokenTargetKey{target.kind, target.key})
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveTokenDelivery() targets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTokenTargets(t *testing.T) {
+	tests := []struct {
+		name            string
+		namespace       string
+		existing        *corev1.Secret
+		wantOwned       bool
+		wantData        map[string][]byte
+		wantAnnotations map[string]string
+	}{
+		{
+			name:      "created within the namespace of the authorization",
+			namespace: "default",
+			wantOwned: true,
+			wantData: map[string][]byte{
+				"token": []byte("new"),
+				"url":   []byte("http://influx:8086"),
+				"org":   []byte("macro"),
+			},
+			wantAnnotations: map[string]string{
+				"example.com/token-for":   "telegraf/0a0b0c0d0e0f0002",
+				authorizationIDAnnotation: "0a0b0c0d0e0f0002",
+			},
+		},
+		{
+			name:      "created in another namespace",
+			namespace: "apps",
+			wantData: map[string][]byte{
+				"token": []byte("new"),
+				"url":   []byte("http://influx:8086"),
+				"org":   []byte("macro"),
+			},
+			wantAnnotations: map[string]string{
+				"example.com/token-for":   "telegraf/0a0b0c0d0e0f0002",
+				authorizationIDAnnotation: "0a0b0c0d0e0f0002",
+			},
+		},
+		{
+			name:      "updated replacing data and merging metadata",
+			namespace: "default",
+			existing: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "default",
+					Name:        "telegraf-primary",
+					Labels:      map[string]string{authorizationNameLabel: "telegraf", authorizationNamespaceLabel: "default"},
+					Annotations: map[string]string{"team": "metrics"},
+				},
+				Data: map[string][]byte{"token": []byte("old"), "stale": []byte("value")},
+			},
+			wantOwned: true,
+			wantData: map[string][]byte{
+				"token": []byte("new"),
+				"url":   []byte("http://influx:8086"),
+				"org":   []byte("macro"),
+			},
+			wantAnnotations: map[string]string{
+				"team":                    "metrics",
+				"example.com/token-for":   "telegraf/0a0b0c0d0e0f0002",
+				authorizationIDAnnotation: "0a0b0c0d0e0f0002",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []client.Object
+			if tt.existing != nil {
+				objs = append(objs, tt.existing)
+			}
+
+			c := newFakeClient(t, objs...)
+			r := &AuthorizationReconciler{Client: c, Scheme: c.Scheme()}
+
+			authorization := tokenSecretAuthorization(tt.namespace, "telegraf-{{ .Instance.Name }}")
+			spec := authorization.Spec.Token.SecretSpec
+			spec.Labels = map[string]string{"app.kubernetes.io/instance": "{{ .Instance.Name }}"}
+			spec.Annotations = map[string]string{"example.com/token-for": "{{ .Authorization.Name }}/{{ .Authorization.ID }}"}
+			spec.Data = map[string]string{"url": "{{ .Instance.Address }}", "org": "{{ .Organization.Name }}"}
+			authorization.Spec.Token.ConfigMaps = []paradoxv1alpha1.ConfigMapSpec{{
+				TargetSpec: paradoxv1alpha1.TargetSpec{
+					Namespace:    tt.namespace,
+					NameTemplate: "telegraf-{{ .Instance.Name }}",
+					Data:         map[string]string{"url": "{{ .Instance.Address }}", "token": "{{ .Token }}"},
+				},
+			}}
+
+			delivery, err := r.resolveTokenDelivery(context.Background(), authorization, tokenSecretTemplateData())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := r.writeTokenTargets(context.Background(), authorization, delivery, "0a0b0c0d0e0f0002", "new"); err != nil {
+				t.Fatalf("writeTokenTargets() error = %v", err)
+			}
+
+			key := types.NamespacedName{Namespace: tt.namespace, Name: "telegraf-primary"}
+
+			var secret corev1.Secret
+			if err := c.Get(context.Background(), key, &secret); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(secret.Data, tt.wantData) {
+				t.Errorf("secret data = %q, want %q", secret.Data, tt.wantData)
+			}
+
+			for k, v := range tokenTargetLabels(authorization, "influx", "primary") {
+				if secret.Labels[k] != v {
+					t.Errorf("secret label %s = %q, want %q", k, secret.Labels[k], v)
+				}
+			}
+
+			if got := secret.Labels["app.kubernetes.io/instance"]; got != "primary" {
+				t.Errorf("secret templated label = %q, want %q", got, "primary")
+			}
+
+			if !reflect.DeepEqual(secret.Annotations, tt.wantAnnotations) {
+				t.Errorf("secret annotations = %v, want %v", secret.Annotations, tt.wantAnnotations)
+			}
+
+			if owned := metav1.IsControlledBy(&secret, authorization); owned != tt.wantOwned {
+				t.Errorf("secret controlled by authorization = %v, want %v", owned, tt.wantOwned)
+			}
+
+			var configMap corev1.ConfigMap
+			if err := c.Get(context.Background(), key, &configMap); err != nil {
+				t.Fatal(err)
+			}
+
+			// config maps are never supplied the token
+			wantConfigMapData := map[string]string{"url": "http://influx:8086", "token": ""}
+			if !reflect.DeepEqual(configMap.Data, wantConfigMapData) {
+				t.Errorf("config map data = %q, want %q", configMap.Data, wantConfigMapData)
+			}
+
+			token, ok, err := r.readToken(context.Background(), delivery, "0a0b0c0d0e0f0002")
+			if err != nil || !ok || token != "new" {
+				t.Errorf("readToken() = %q, %v, %v, want %q, true, nil", token, ok, err, "new")
+			}
+		})
+	}
+}
+
+func TestReadToken(t *testing.T) {
+	secret := func(name, id, token string) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Data:       map[string][]byte{"token": []byte(token)},
+		}
+
+		if id != "" {
+			secret.Annotations = map[string]string{authorizationIDAnnotation: id}
+		}
+
+		return secret
+	}
+
+	tests := []struct {
+		name      string
+		existing  []client.Object
+		wantToken string
+		wantOK    bool
+	}{
+		{
+			name: "no secrets",
+		},
+		{
+			name:      "written with the current authorization",
+			existing:  []client.Object{secret("first-primary", "current", "t1")},
+			wantToken: "t1",
+			wantOK:    true,
+		},
+		{
+			name: "written with a previous authorization",
+			existing: []client.Object{
+				secret("first-primary", "previous", "t0"),
+				secret("second-primary", "current", "t1"),
+			},
+			wantToken: "t1",
+			wantOK:    true,
+		},
+		{
+			name:     "only written with a previous authorization",
+			existing: []client.Object{secret("first-primary", "previous", "t0")},
+		},
+		{
+			name:      "written before the authorization was recorded",
+			existing:  []client.Object{secret("first-primary", "", "t1")},
+			wantToken: "t1",
+			wantOK:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeClient(t, tt.existing...)
+			r := &AuthorizationReconciler{Client: c, Scheme: c.Scheme()}
+
+			authorization := tokenSecretAuthorization("default", "first-{{ .Instance.Name }}")
+			authorization.Spec.Token.Secrets = []paradoxv1alpha1.SecretSpec{{
+				TargetSpec: paradoxv1alpha1.TargetSpec{Namespace: "default", NameTemplate: "second-{{ .Instance.Name }}"},
+				Key:        "token",
+			}}
+
+			delivery, err := r.resolveTokenDelivery(context.Background(), authorization, tokenSecretTemplateData())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token, ok, err := r.readToken(context.Background(), delivery, "current")
+			if err != nil {
+				t.Fatalf("readToken() error = %v", err)
+			}
+
+			if token != tt.wantToken || ok != tt.wantOK {
+				t.Errorf("readToken() = %q, %v, want %q, %v", token, ok, tt.wantToken, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestWriteTokenTargetsConflict(t *testing.T) {
+	authorization := tokenSecretAuthorization("default", "telegraf")
+
+	tests := []struct {
+		name          string
+		labels        map[string]string
+		wantConflicts []string
+	}{
+		{
+			name:          "unlabelled",
+			wantConflicts: []string{"secret default/telegraf"},
+		},
+		{
+			name:          "another authorization",
+			labels:        map[string]string{authorizationNameLabel: "grafana", authorizationNamespaceLabel: "default"},
+			wantConflicts: []string{"secret default/telegraf"},
+		},
+		{
+			name:          "another namespace",
+			labels:        map[string]string{authorizationNameLabel: "telegraf", authorizationNamespaceLabel: "apps"},
+			wantConflicts: []string{"secret default/telegraf"},
+		},
+		{
+			name:          "another targeted instance",
+			labels:        tokenTargetLabels(authorization, "influx", "secondary"),
+			wantConflicts: []string{"secret default/telegraf (instance influx/secondary)"},
+		},
+		{
+			name:   "an instance no longer targeted",
+			labels: tokenTargetLabels(authorization, "influx", "retired"),
+		},
+		{
+			name:   "the same instance",
+			labels: tokenTargetLabels(authorization, "influx", "primary"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "telegraf", Labels: tt.labels},
+				Data:       map[string][]byte{"password": []byte("application")},
+			}
+
+			c := newFakeClient(t, existing)
+			r := &AuthorizationReconciler{Client: c, Scheme: c.Scheme()}
+
+			delivery, err := r.resolveTokenDelivery(context.Background(), authorization, tokenSecretTemplateData())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			delivery.targeted = map[types.NamespacedName]instanceTarget{
+				{Namespace: "influx", Name: "primary"}:   {},
+				{Namespace: "influx", Name: "secondary"}: {},
+			}
+
+			_, err = r.writeTokenTargets(context.Background(), authorization, delivery, "0a0b0c0d0e0f0002", "new")
+			if conflicts := tokenTargetConflicts(err); !reflect.DeepEqual(conflicts, tt.wantConflicts) {
+				t.Fatalf("writeTokenTargets() conflicts = %v, want %v (error %v)", conflicts, tt.wantConflicts, err)
+			}
+
+			var secret corev1.Secret
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(existing), &secret); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantConflicts == nil {
+				if !reflect.DeepEqual(secret.Labels, tokenTargetLabels(authorization, "influx", "primary")) {
+					t.Errorf("secret labels = %v, want those of the instance", secret.Labels)
+				}
+
+				return
+			}
+
+			if !reflect.DeepEqual(secret.Data, existing.Data) || !reflect.DeepEqual(secret.Labels, tt.labels) {
+				t.Errorf("conflicting secret modified: labels %v, data %q", secret.Labels, secret.Data)
+			}
+		})
+	}
+}
+
+func TestClaimTokenTargets(t *testing.T) {
+	r := &AuthorizationReconciler{Client: newFakeClient(t)}
+	claims := map[tokenTargetKey]types.NamespacedName{}
+
+	deliver := func(instance, nameTemplate string) tokenDelivery {
+		data := tokenSecretTemplateData()
+		data.Instance.Name = instance
+
+		authorization := tokenSecretAuthorization("default", nameTemplate)
+		authorization.Spec.Token.ConfigMaps = []paradoxv1alpha1.ConfigMapSpec{{
+			TargetSpec: paradoxv1alpha1.TargetSpec{Namespace: "default", NameTemplate: "influx-{{ .Instance.Name }}"},
+		}}
+
+		delivery, err := r.resolveTokenDelivery(context.Background(), authorization, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return delivery
+	}
+
+	if err := claimTokenTargets(claims, deliver("primary", "telegraf")); err != nil {
+		t.Fatalf("claimTokenTargets() error = %v", err)
+	}
+
+	// claiming again for the same instance, as on a later attempt, succeeds
+	if err := claimTokenTargets(claims, deliver("primary", "telegraf")); err != nil {
+		t.Fatalf("claimTokenTargets() error = %v", err)
+	}
+
+	if err := claimTokenTargets(claims, deliver("secondary", "telegraf-{{ .Instance.Name }}")); err != nil {
+		t.Fatalf("claimTokenTargets() error = %v", err)
+	}
+
+	err := claimTokenTargets(claims, deliver("tertiary", "telegraf"))
+	if want := []string{"secret default/telegraf (instance influx/primary)"}; !reflect.DeepEqual(tokenTargetConflicts(err), want) {
+		t.Fatalf("claimTokenTargets() conflicts = %v, want %v (error %v)", tokenTargetConflicts(err), want, err)
+	}
+
+	// nothing is claimed for an instance whose targets collide
+	if _, ok := claims[tokenTargetKey{tokenTargetConfigMap, types.NamespacedName{Namespace: "default", Name: "influx-tertiary"}}]; ok {
+		t.Error("targets claimed for an instance whose targets collide")
+	}
+
+	if got, want := len(claims), 4; got != want {
+		t.Errorf("%d targets claimed, want %d", got, want)
+	}
+}
+
+func TestWriteTokenTargetsTemplateError(t *testing.T) {
+	c := newFakeClient(t)
+	r := &AuthorizationReconciler{Client: c, Scheme: c.Scheme()}
+
+	authorization := tokenSecretAuthorization("default", "telegraf-{{ .Instance.Name }}")
+	authorization.Spec.Token.SecretSpec.Data = map[string]string{"bucket": "{{ .Bucket.Retention }}"}
+
+	delivery, err := r.resolveTokenDelivery(context.Background(), authorization, tokenSecretTemplateData())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.writeTokenTargets(context.Background(), authorization, delivery, "0a0b0c0d0e0f0002", "new"); err == nil {
+		t.Fatal("writeTokenTargets() expected an error rendering an unknown field")
+	}
+
+	err = c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "telegraf-primary"}, &corev1.Secret{})
+	if err == nil {
+		t.Error("secret written despite failing to render its data")
+	}
+}
+
+func TestDeleteTokenTargets(t *testing.T) {
+	authorization := tokenSecretAuthorization("default", "telegraf-{{ .Instance.Name }}")
+
+	secret := func(namespace, name string, labels map[string]string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+	}
+
+	configMap := func(namespace, name string, labels map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+	}
+
+	c := newFakeClient(t,
+		secret("default", "telegraf-primary", tokenTargetLabels(authorization, "influx", "primary")),
+		secret("default", "telegraf-retired", tokenTargetLabels(authorization, "influx", "retired")),
+		secret("apps", "telegraf-retired", tokenTargetLabels(authorization, "influx", "retired")),
+		secret("default", "unrelated", map[string]string{authorizationNameLabel: "other", authorizationNamespaceLabel: "default"}),
+		secret("default", "unlabelled", nil),
+		configMap("default", "telegraf-primary", tokenTargetLabels(authorization, "influx", "primary")),
+		configMap("default", "telegraf-retired", tokenTargetLabels(authorization, "influx", "retired")),
+	)
+	r := &AuthorizationReconciler{Client: c, Scheme: c.Scheme()}
+
+	keep := map[tokenTargetKey]struct{}{
+		{tokenTargetSecret, types.NamespacedName{Namespace: "default", Name: "telegraf-primary"}}:    {},
+		{tokenTargetConfigMap, types.NamespacedName{Namespace: "default", Name: "telegraf-primary"}}: {},
+	}
+	if err := r.deleteTokenTargets(context.Background(), authorization, keep); err != nil {
+		t.Fatalf("deleteTokenTargets() error = %v", err)
+	}
+
+	tests := []struct {
+		obj  client.Object
+		key  types.NamespacedName
+		want bool
+	}{
+		{obj: &corev1.Secret{}, key: types.NamespacedName{Namespace: "default", Name: "telegraf-primary"}, want: true},
+		{obj: &corev1.Secret{}, key: types.NamespacedName{Namespace: "default", Name: "telegraf-retired"}},
+		{obj: &corev1.Secret{}, key: types.NamespacedName{Namespace: "apps", Name: "telegraf-retired"}},
+		{obj: &corev1.Secret{}, key: types.NamespacedName{Namespace: "default", Name: "unrelated"}, want: true},
+		{obj: &corev1.Secret{}, key: types.NamespacedName{Namespace: "default", Name: "unlabelled"}, want: true},
+		{obj: &corev1.ConfigMap{}, key: types.NamespacedName{Namespace: "default", Name: "telegraf-primary"}, want: true},
+		{obj: &corev1.ConfigMap{}, key: types.NamespacedName{Namespace: "default", Name: "telegraf-retired"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(reflect.TypeOf(tt.obj).Elem().Name()+"/"+tt.key.String(), func(t *testing.T) {
+			err := c.Get(context.Background(), tt.key, tt.obj)
+			if exists := err == nil; exists != tt.want {
+				t.Errorf("object exists = %v, want %v (error %v)", exists, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestTokenTemplateData(t *testing.T) {
+	bucket := func(name, influxName string, id paradoxv1alpha1.InfluxID) *paradoxv1alpha1.Bucket {
+		return &paradoxv1alpha1.Bucket{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       paradoxv1alpha1.BucketSpec{Name: influxName},
+			Status: paradoxv1alpha1.BucketStatus{
+				Instances: paradoxv1alpha1.Instances{"influx": {"primary": {ID: &id}}},
+			},
+		}
+	}
+
+	c := newFakeClient(t,
+		bucket("metrics", "telegraf-metrics", "0a0b0c0d0e0f0010"),
+		bucket("logs", "telegraf-logs", "0a0b0c0d0e0f0011"),
+	)
+	r := &AuthorizationReconciler{Client: c, Scheme: c.Scheme()}
+
+	authorization := tokenSecretAuthorization("default", "telegraf-{{ .Instance.Name }}")
+	for _, name := range []string{"metrics", "logs", "metrics", ""} {
+		authorization.Spec.Permissions = append(authorization.Spec.Permissions, paradoxv1alpha1.Permission{
+			Action:   "write",
+			Resource: paradoxv1alpha1.Resource{ResourceType: paradoxv1alpha1.ResourceTypeBuckets, Name: name},
+		})
+	}
+
+	organization := &paradoxv1alpha1.Organization{Spec: paradoxv1alpha1.OrganizationSpec{Name: "macro"}}
+	instance := &paradoxv1alpha1.Instance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "influx", Name: "primary"},
+		Spec:       paradoxv1alpha1.InstanceSpec{Address: "http://influx:8086"},
+	}
+	orgID := paradoxv1alpha1.InfluxID("0a0b0c0d0e0f0001")
+
+	got, err := r.tokenTemplateData(context.Background(), authorization, organization, instance, &orgID)
+	if err != nil {
+		t.Fatalf("tokenTemplateData() error = %v", err)
+	}
+
+	want := tokenSecretTemplateData()
+	want.Buckets = []resourceTemplateData{
+		{Name: "telegraf-metrics", ID: "0a0b0c0d0e0f0010"},
+		{Name: "telegraf-logs", ID: "0a0b0c0d0e0f0011"},
+	}
+	want.Bucket = want.Buckets[0]
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenTemplateData() = %+v, want %+v", got, want)
+	}
+
+	authorization.Spec.Permissions[0].Resource.Name = "missing"
+	if _, err := r.tokenTemplateData(context.Background(), authorization, organization, instance, &orgID); err == nil {
+		t.Error("tokenTemplateData() expected an error for a missing bucket")
+	}
+}
@@ -0,0 +1,395 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	paradoxv1alpha1 "macro.re/paradox/api/v1alpha1"
+)
+
+var (
+	// ErrUserExists is returned when a user of the same name already exists
+	// in a target instance but was not created by the User resource.
+	ErrUserExists = errors.New("user already exists in the target instance and spec.adopt is not set")
+	// ErrOnboardingUser is returned when the user is the onboarding user of
+	// a target instance, whose password holds the operator credentials.
+	ErrOnboardingUser = errors.New("user is the onboarding user of the target instance")
+)
+
+// UserReconciler reconciles a User object
+type UserReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Clients is the pool of Influx clients shared by every reconciler.
+	Clients *ClientPool
+	// ResyncInterval is the interval at which each user is reconciled
+	// against its target instances, unless overridden by annotation.
+	ResyncInterval time.Duration
+}
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=users,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=users/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=users/finalizers,verbs=update
+
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations,verbs=get
+//+kubebuilder:rbac:groups=paradox.macro.re,resources=organizations/status,verbs=get
+
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// Users are created within the target instances of their organization using the
+// operator credentials of each instance, as users are not scoped to an organization.
+// Users which already exist are compared against the spec and any drift in their
+// name or status is corrected. As Influx does not return passwords, the password
+// is rewritten whenever the content of the referenced Secret changes.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
+func (r *UserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var user paradoxv1alpha1.User
+	if err := r.Get(ctx, req.NamespacedName, &user); err != nil {
+		log.Error(err, "unable to fetch user")
+
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	log = log.WithValues("user", user)
+
+	if !user.ObjectMeta.DeletionTimestamp.IsZero() {
+		if err := finalize(ctx, r.Client, &user, user.Spec.DeletionPolicy, func() error {
+			return r.deleteInstanceUsers(ctx, &user)
+		}); err != nil {
+			log.Error(err, "failed to finalize user")
+
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if err := syncFinalizer(ctx, r.Client, &user, user.Spec.DeletionPolicy); err != nil {
+		log.Error(err, "failed to update finalizers")
+
+		return ctrl.Result{}, err
+	}
+
+	var organization paradoxv1alpha1.Organization
+	if err := r.Get(ctx, types.NamespacedName{
+		Namespace: req.NamespacedName.Namespace,
+		Name:      user.Spec.Organization,
+	}, &organization); err != nil {
+		log.Error(err, "unable to fetch organization")
+
+		return ctrl.Result{}, client.IgnoreNotFound(r.updateStatus(ctx, &user, user.Status.Instances, user.Status.PasswordHashes, fmt.Errorf("organization %q: %w", user.Spec.Organization, err)))
+	}
+
+	password, passwordHash, err := r.userPassword(ctx, user.Spec)
+	if err != nil {
+		log.Error(err, "unable to resolve user password")
+
+		return ctrl.Result{}, r.updateStatus(ctx, &user, user.Status.Instances, user.Status.PasswordHashes, err)
+	}
+
+	status := domain.UserStatus(activityStatus(user.Spec.Status))
+
+	var (
+		mu     sync.Mutex
+		drift  []string
+		hashes = user.Status.PasswordHashes.DeepCopy()
+	)
+
+	if hashes == nil {
+		hashes = paradoxv1alpha1.InstanceHashes{}
+	}
+
+	instances, err := reconcileInstances(ctx, r.Client, r.Clients, &organization, user.Status.Instances, func(instance *paradoxv1alpha1.Instance, client influxdb.Client) (*paradoxv1alpha1.InfluxID, error) {
+		namespace, name := instance.ObjectMeta.Namespace, instance.ObjectMeta.Name
+
+		// the onboarding user holds the operator credentials of the instance
+		if onboarding := instance.Spec.Onboarding; onboarding != nil && onboarding.Username == user.Spec.Name {
+			return nil, ErrOnboardingUser
+		}
+
+		client, release, err := userClient(ctx, r.Client, r.Clients, instance, client)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		usersAPI := client.UsersAPI()
+		existing, err := findUser(ctx, usersAPI, user.Status.Instances[namespace][name].ID, user.Spec.Name, user.Spec.Adopt)
+		if err != nil {
+			return nil, err
+		}
+
+		created := existing == nil
+		if created {
+			existing, err = usersAPI.CreateUser(ctx, &domain.User{
+				Name:   user.Spec.Name,
+				Status: &status,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if existing.Id == nil {
+				return nil, fmt.Errorf("creating user %q: %w", user.Spec.Name, ErrInfluxUnexpectedResponse)
+			}
+		} else if changes := userDrift(existing, user.Spec.Name, status); len(changes) > 0 {
+			existing.Name = user.Spec.Name
+			existing.Status = &status
+
+			existing, err = usersAPI.UpdateUser(ctx, existing)
+			if err != nil {
+				return nil, err
+			}
+
+			message := fmt.Sprintf("corrected drift in instance %s/%s: %s", namespace, name, strings.Join(changes, ", "))
+			r.Recorder.Event(&user, corev1.EventTypeNormal, "DriftCorrected", message)
+
+			mu.Lock()
+			drift = append(drift, message)
+			mu.Unlock()
+		}
+
+		id := fromStringPtr[paradoxv1alpha1.InfluxID](existing.Id)
+
+		// the password is rewritten to each instance whenever it differs from
+		// the one last written to it
+		mu.Lock()
+		previousHash := hashes.Get(namespace, name)
+		mu.Unlock()
+
+		if user.Spec.PasswordSecretRef != nil && (created || previousHash != passwordHash) {
+			if err := usersAPI.UpdateUserPasswordWithID(ctx, *existing.Id, password); err != nil {
+				return id, fmt.Errorf("setting password: %w", err)
+			}
+		}
+
+		mu.Lock()
+		hashes.Set(namespace, name, passwordHash)
+		mu.Unlock()
+
+		return id, nil
+	})
+	if err != nil {
+		log.Error(err, "error while configuring instances")
+	}
+
+	setDriftCondition(&user.Status.Conditions, user.Generation, drift)
+
+	return resyncResult(ctx, &user, r.ResyncInterval), r.updateStatus(ctx, &user, instances, hashes, err)
+}
+
+// updateStatus records instances and password hashes along with the conditions
+// derived from reconcileErr in the status of user. The reconcile error is returned
+// unless the status update itself fails.
+func (r *UserReconciler) updateStatus(ctx context.Context, user *paradoxv1alpha1.User, instances paradoxv1alpha1.Instances, hashes paradoxv1alpha1.InstanceHashes, reconcileErr error) error {
+	if instances == nil {
+		instances = paradoxv1alpha1.Instances{}
+	}
+
+	// hashes are only kept for instances which are still recorded
+	hashes.Retain(instances)
+
+	user.Status.ObservedGeneration = user.Generation
+	user.Status.Instances = instances
+	user.Status.PasswordHashes = hashes
+	setConditions(&user.Status.Conditions, user.Generation, instances, reconcileErr)
+
+	if err := r.Status().Update(ctx, user); err != nil {
+		log.FromContext(ctx).Error(err, "failed to update status")
+
+		return err
+	}
+
+	return reconcileErr
+}
+
+// deleteInstanceUsers removes the user from every target instance in which
+// it has previously been recorded, which also removes it from every organization
+// it is a member or owner of.
+func (r *UserReconciler) deleteInstanceUsers(ctx context.Context, user *paradoxv1alpha1.User) error {
+	deletion := instanceDeletion{
+		Client:    r.Client,
+		Clients:   r.Clients,
+		Recorder:  r.Recorder,
+		Object:    user,
+		Noun:      "user",
+		Instances: user.Status.Instances,
+	}
+
+	return deletion.deleteWithin(ctx, user.Spec.Organization, func(instance *paradoxv1alpha1.Instance, client influxdb.Client, id paradoxv1alpha1.InfluxID) error {
+		client, release, err := userClient(ctx, r.Client, r.Clients, instance, client)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		if err := client.UsersAPI().DeleteUserWithID(ctx, string(id)); err != nil && !isInfluxNotFound(err) {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// userPassword returns the password declared by spec along with its hash, which
+// are both empty when the user declares no password.
+func (r *UserReconciler) userPassword(ctx context.Context, spec paradoxv1alpha1.UserSpec) (string, string, error) {
+	if spec.PasswordSecretRef == nil {
+		return "", "", nil
+	}
+
+	password, err := resolveSecretKey(ctx, r.Client, *spec.PasswordSecretRef)
+	if err != nil {
+		return "", "", err
+	}
+
+	hash := sha256.Sum256([]byte(password))
+
+	return password, hex.EncodeToString(hash[:]), nil
+}
+
+// userClient returns a client for instance authorized using the operator
+// credentials of the instance, as users are global to an instance and organization
+// scoped credentials may not be permitted to manage them. The client authorized
+// for the organization is returned when the instance defines no credentials.
+// The returned function must be called once the client is no longer used.
+func userClient(ctx context.Context, c client.Client, pool *ClientPool, instance *paradoxv1alpha1.Instance, orgClient influxdb.Client) (influxdb.Client, func(), error) {
+	if instance.Spec.Authorization == nil {
+		return orgClient, func() {}, nil
+	}
+
+	return operatorClient(ctx, c, pool, instance)
+}
+
+// findUser returns the user previously recorded as id, or nil when it no longer
+// exists. An existing user named name, which has not been recorded, is only
+// returned when adopt is true, and ErrUserExists is returned otherwise.
+func findUser(ctx context.Context, usersAPI api.UsersAPI, id *paradoxv1alpha1.InfluxID, name string, adopt bool) (*domain.User, error) {
+	if id != nil {
+		user, err := usersAPI.FindUserByID(ctx, string(*id))
+		if err == nil {
+			return user, nil
+		}
+
+		if !isInfluxNotFound(err) {
+			return nil, err
+		}
+	}
+
+	user, err := usersAPI.FindUserByName(ctx, name)
+	if err != nil {
+		if isInfluxNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if !adopt {
+		return nil, fmt.Errorf("user %q: %w", name, ErrUserExists)
+	}
+
+	return user, nil
+}
+
+// userDrift describes each difference between the existing user and the
+// desired name and status.
+func userDrift(existing *domain.User, name string, status domain.UserStatus) (changes []string) {
+	if existing.Name != name {
+		changes = append(changes, fmt.Sprintf("name %q -> %q", existing.Name, name))
+	}
+
+	// users without a status are active
+	current := domain.UserStatusActive
+	if existing.Status != nil {
+		current = *existing.Status
+	}
+
+	if current != status {
+		changes = append(changes, fmt.Sprintf("status %q -> %q", current, status))
+	}
+
+	return changes
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *UserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.User{}, orgField, func(rawObj client.Object) []string {
+		user := rawObj.(*paradoxv1alpha1.User)
+		if user.Spec.Organization == "" {
+			return nil
+		}
+
+		return []string{user.Spec.Organization}
+	}); err != nil {
+		return err
+	}
+
+	if err := indexer.IndexField(context.Background(), &paradoxv1alpha1.User{}, secretField, func(rawObj client.Object) []string {
+		user := rawObj.(*paradoxv1alpha1.User)
+		if ref := user.Spec.PasswordSecretRef; ref != nil {
+			return []string{ref.Namespace + "/" + ref.Name}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&paradoxv1alpha1.User{}, builder.WithPredicates(specOrAnnotationChanged())).
+		Watches(
+			&source.Kind{Type: &paradoxv1alpha1.Organization{}},
+			handler.EnqueueRequestsFromMapFunc(findForField(r.Client, &paradoxv1alpha1.UserList{}, orgField)),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(findForReference(r.Client, &paradoxv1alpha1.UserList{}, secretField)),
+		).
+		Complete(r)
+}
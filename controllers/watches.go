@@ -0,0 +1,72 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// findForField returns a map function which requests every object of the same
+// type as list within the namespace of an object which references it by name
+// in the indexed field.
+func findForField(c client.Client, list client.ObjectList, field string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		return listRequests(c, list,
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{field: obj.GetName()},
+		)
+	}
+}
+
+// findForReference returns a map function which requests every object of the
+// same type as list which references an object, such as a Secret, by its
+// namespaced name in the indexed field.
+func findForReference(c client.Client, list client.ObjectList, field string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		return listRequests(c, list,
+			client.MatchingFields{field: client.ObjectKeyFromObject(obj).String()},
+		)
+	}
+}
+
+// listRequests returns a request for every object of the same type as list
+// which matches opts.
+func listRequests(c client.Client, list client.ObjectList, opts ...client.ListOption) []reconcile.Request {
+	objects := list.DeepCopyObject().(client.ObjectList)
+	if err := c.List(context.TODO(), objects, opts...); err != nil {
+		return []reconcile.Request{}
+	}
+
+	items, err := meta.ExtractList(objects)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(client.Object); ok {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+		}
+	}
+
+	return requests
+}
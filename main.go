@@ -19,6 +19,7 @@ package main
 import (
 	"flag"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -52,11 +53,18 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var instanceProbeInterval time.Duration
+	var resyncInterval time.Duration
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&instanceProbeInterval, "instance-probe-interval", controllers.DefaultInstanceProbeInterval,
+		"The interval at which each Influx instance is probed for health and setup state.")
+	flag.DurationVar(&resyncInterval, "resync-interval", controllers.DefaultResyncInterval,
+		"The interval at which organizations, buckets, authorizations, tasks, checks, notifications, dashboards, labels and users are re-checked against each Influx instance. "+
+			"Overridden per resource by the "+controllers.ResyncIntervalAnnotation+" annotation, 0 disables resync.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -78,34 +86,164 @@ func main() {
 		os.Exit(1)
 	}
 
+	clients := controllers.NewClientPool()
+
 	if err = (&controllers.OrganizationReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Clients:        clients,
+		Recorder:       mgr.GetEventRecorderFor("organization-controller"),
+		ResyncInterval: resyncInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Organization")
 		os.Exit(1)
 	}
 	if err = (&controllers.BucketReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Clients:        clients,
+		Recorder:       mgr.GetEventRecorderFor("bucket-controller"),
+		ResyncInterval: resyncInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Bucket")
 		os.Exit(1)
 	}
 	if err = (&controllers.AuthorizationReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Clients:        clients,
+		Recorder:       mgr.GetEventRecorderFor("authorization-controller"),
+		ResyncInterval: resyncInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Authorization")
 		os.Exit(1)
 	}
 	if err = (&controllers.InstanceReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Clients:       clients,
+		Recorder:      mgr.GetEventRecorderFor("instance-controller"),
+		ProbeInterval: instanceProbeInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Instance")
 		os.Exit(1)
 	}
+	if err = (&controllers.TaskReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Clients:        clients,
+		Recorder:       mgr.GetEventRecorderFor("task-controller"),
+		ResyncInterval: resyncInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Task")
+		os.Exit(1)
+	}
+	if err = (&controllers.CheckReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Clients:        clients,
+		Recorder:       mgr.GetEventRecorderFor("check-controller"),
+		ResyncInterval: resyncInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Check")
+		os.Exit(1)
+	}
+	if err = (&controllers.NotificationEndpointReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Clients:        clients,
+		Recorder:       mgr.GetEventRecorderFor("notificationendpoint-controller"),
+		ResyncInterval: resyncInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NotificationEndpoint")
+		os.Exit(1)
+	}
+	if err = (&controllers.NotificationRuleReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Clients:        clients,
+		Recorder:       mgr.GetEventRecorderFor("notificationrule-controller"),
+		ResyncInterval: resyncInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NotificationRule")
+		os.Exit(1)
+	}
+	if err = (&controllers.DashboardReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Clients:        clients,
+		Recorder:       mgr.GetEventRecorderFor("dashboard-controller"),
+		ResyncInterval: resyncInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Dashboard")
+		os.Exit(1)
+	}
+	if err = (&controllers.LabelReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Clients:        clients,
+		Recorder:       mgr.GetEventRecorderFor("label-controller"),
+		ResyncInterval: resyncInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Label")
+		os.Exit(1)
+	}
+	if err = (&controllers.UserReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Clients:        clients,
+		Recorder:       mgr.GetEventRecorderFor("user-controller"),
+		ResyncInterval: resyncInterval,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "User")
+		os.Exit(1)
+	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&paradoxv1alpha1.Organization{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Organization")
+			os.Exit(1)
+		}
+		if err = (&paradoxv1alpha1.Bucket{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Bucket")
+			os.Exit(1)
+		}
+		if err = (&paradoxv1alpha1.Authorization{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Authorization")
+			os.Exit(1)
+		}
+		if err = (&paradoxv1alpha1.Instance{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Instance")
+			os.Exit(1)
+		}
+		if err = (&paradoxv1alpha1.Task{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Task")
+			os.Exit(1)
+		}
+		if err = (&paradoxv1alpha1.Check{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Check")
+			os.Exit(1)
+		}
+		if err = (&paradoxv1alpha1.NotificationEndpoint{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NotificationEndpoint")
+			os.Exit(1)
+		}
+		if err = (&paradoxv1alpha1.NotificationRule{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NotificationRule")
+			os.Exit(1)
+		}
+		if err = (&paradoxv1alpha1.Dashboard{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Dashboard")
+			os.Exit(1)
+		}
+		if err = (&paradoxv1alpha1.Label{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Label")
+			os.Exit(1)
+		}
+		if err = (&paradoxv1alpha1.User{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "User")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {